@@ -0,0 +1,139 @@
+//go:build js && wasm
+
+// Command wasm compiles repeat rules to WebAssembly, so the web UI can preview
+// next dates and validate rules in the browser without calling the API:
+//
+//	GOOS=js GOARCH=wasm go build -o web/wasm/repeat.wasm ./cmd/wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" web/wasm/
+//
+// User-defined presets live in the database and are not available here, built-in aliases are.
+// The holidays calendar and the time zone of the server are passed in from /api/repeat/calendar
+// with setWorkCalendar and setTimeZone, without them only weekends are days off
+// and "today" is taken in the browser time zone.
+package main
+
+import (
+	"errors"
+	"syscall/js"
+	"time"
+	// Browsers have no time zone database
+	_ "time/tzdata"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/calendar"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
+)
+
+// location - time zone of "today", the browser one unless set by setTimeZone
+var location = time.Local
+
+func main() {
+	js.Global().Set("nextDate", js.FuncOf(nextDate))
+	js.Global().Set("validateRule", js.FuncOf(validateRule))
+	js.Global().Set("setWorkCalendar", js.FuncOf(setWorkCalendar))
+	js.Global().Set("setTimeZone", js.FuncOf(setTimeZone))
+
+	// Let the page know the functions are ready
+	if ready := js.Global().Get("onRepeatReady"); ready.Type() == js.TypeFunction {
+		ready.Invoke()
+	}
+
+	// Keep the functions available
+	select {}
+}
+
+// nextDate(now, date, repeat) returns the next date as a string, or an Error.
+// Empty now means today in the time zone set by setTimeZone.
+func nextDate(this js.Value, args []js.Value) any {
+	if len(args) != 3 {
+		return jsError(errors.New("nextDate expects now, date and repeat"))
+	}
+
+	now := time.Now().In(location)
+	if nowStr := args[0].String(); nowStr != "" {
+		var err error
+		now, err = time.Parse(repeat.DateFormat, nowStr)
+		if err != nil {
+			return jsError(errors.New("invalid date format for 'now' parameter"))
+		}
+	}
+
+	next, err := repeat.NextDate(now, args[1].String(), args[2].String())
+	if err != nil {
+		return jsError(err)
+	}
+	return next
+}
+
+// validateRule(repeat) returns an object like /api/repeat/validate:
+// {valid: true} or {valid: false, error, code, token, offset}
+func validateRule(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return jsError(errors.New("validateRule expects repeat"))
+	}
+
+	err := repeat.Validate(args[0].String(), time.Now().In(location))
+	if err == nil {
+		return map[string]any{"valid": true}
+	}
+
+	result := map[string]any{"valid": false, "error": err.Error(), "code": repeat.CodeValue, "token": "", "offset": 0}
+	var ruleErr *repeat.RuleError
+	if errors.As(err, &ruleErr) {
+		result["code"] = ruleErr.Code
+		result["token"] = ruleErr.Token
+		result["offset"] = ruleErr.Offset
+	}
+	return result
+}
+
+// setWorkCalendar(holidays, workdays) sets the calendar of business day rules from arrays of dates
+// like in /api/repeat/calendar, returns an Error for invalid dates
+func setWorkCalendar(this js.Value, args []js.Value) any {
+	if len(args) != 2 {
+		return jsError(errors.New("setWorkCalendar expects holidays and workdays"))
+	}
+
+	holidays, err := calendar.FromDates(jsStrings(args[0]), jsStrings(args[1]))
+	if err != nil {
+		return jsError(err)
+	}
+	repeat.SetWorkCalendar(holidays)
+	return nil
+}
+
+// setTimeZone(name) sets IANA time zone of "today", empty name is the browser time zone.
+// Returns an Error for unknown time zones.
+func setTimeZone(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return jsError(errors.New("setTimeZone expects a time zone"))
+	}
+
+	name := args[0].String()
+	if name == "" {
+		location = time.Local
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return jsError(errors.New("unknown time zone " + name))
+	}
+	location = loc
+	return nil
+}
+
+// jsStrings converts a JavaScript array to strings, undefined and null give no strings
+func jsStrings(value js.Value) []string {
+	if value.IsUndefined() || value.IsNull() {
+		return nil
+	}
+	strings := make([]string, value.Length())
+	for i := range strings {
+		strings[i] = value.Index(i).String()
+	}
+	return strings
+}
+
+// jsError converts an error to a JavaScript Error
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
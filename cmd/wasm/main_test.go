@@ -0,0 +1,71 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"syscall/js"
+	"testing"
+	"time"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
+	"github.com/stretchr/testify/assert"
+)
+
+// call calls the exported function with JavaScript values of the arguments
+func call(fn func(js.Value, []js.Value) any, args ...any) any {
+	values := make([]js.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, js.ValueOf(arg))
+	}
+	return fn(js.Undefined(), values)
+}
+
+// isError checks if the result is a JavaScript Error
+func isError(result any) bool {
+	value, ok := result.(js.Value)
+	return ok && value.InstanceOf(js.Global().Get("Error"))
+}
+
+func TestNextDate(t *testing.T) {
+	assert.Equal(t, "20240127", call(nextDate, "20240126", "20240126", "d 1"))
+	assert.True(t, isError(call(nextDate, "20240126", "20240126", "d 401")))
+	assert.True(t, isError(call(nextDate, "2024-01-26", "20240126", "d 1")))
+	assert.True(t, isError(call(nextDate, "20240126")))
+}
+
+func TestValidateRule(t *testing.T) {
+	assert.Equal(t, map[string]any{"valid": true}, call(validateRule, "w 1,3"))
+
+	result, ok := call(validateRule, "m 1 x").(map[string]any)
+	if assert.True(t, ok) {
+		assert.Equal(t, false, result["valid"])
+		assert.Equal(t, "x", result["token"])
+		assert.Equal(t, 4, result["offset"])
+	}
+}
+
+func TestSetWorkCalendar(t *testing.T) {
+	t.Cleanup(func() { repeat.SetWorkCalendar(nil) })
+
+	assert.Equal(t, "20240129", call(nextDate, "20240126", "20240125", "b 1"))
+
+	assert.Nil(t, call(setWorkCalendar, []any{"20240129"}, []any{"20240127"}))
+	assert.Equal(t, "20240127", call(nextDate, "20240126", "20240125", "b 1"))
+	assert.Equal(t, "20240130", call(nextDate, "20240127", "20240125", "b 1"))
+
+	assert.True(t, isError(call(setWorkCalendar, []any{"2024-01-29"}, nil)))
+	assert.True(t, isError(call(setWorkCalendar, []any{})))
+}
+
+func TestSetTimeZone(t *testing.T) {
+	t.Cleanup(func() { location = time.Local })
+
+	assert.Nil(t, call(setTimeZone, "Asia/Vladivostok"))
+	assert.Equal(t, "Asia/Vladivostok", location.String())
+	today := time.Now().In(location)
+	assert.Equal(t, today.AddDate(0, 0, 1).Format(repeat.DateFormat), call(nextDate, "", today.Format(repeat.DateFormat), "d 1"))
+
+	assert.True(t, isError(call(setTimeZone, "Nowhere/City")))
+	assert.Nil(t, call(setTimeZone, ""))
+	assert.Equal(t, time.Local, location)
+}
@@ -7,28 +7,47 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	_ "time/tzdata"
 
+	"github.com/AngryM0e/ya-p-golang-final/pkg/config"
 	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
 	"github.com/AngryM0e/ya-p-golang-final/pkg/server"
 )
 
 const (
-	defaultPort   = 7540
-	webDir        = "web"
-	defaultDBfile = "scheduler.db"
+	defaultPort       = 7540
+	webDir            = "web"
+	defaultDBfile     = "scheduler.db"
+	defaultConfigFile = "configs/config.yaml"
 )
 
 func main() {
 	port := getPort()
 	dbPath := getAbsolutePath(defaultDBfile)
 	
+	// CLI mode: migrate status|up
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:], dbPath); err != nil {
+			log.Fatal("Migration error: ", err)
+		}
+		return
+	}
+
 	log.Printf("Starting server on port %d", port)
 	log.Printf("Using database: %s", dbPath)
+
+	appCfg, err := config.Load(getAbsolutePath(defaultConfigFile))
+	if err != nil {
+		log.Fatal("Config loading error:", err)
+	}
 	
 	cfg := server.Config{
 		Port:   port,
 		WebDir: webDir,
 		DBPath: dbPath,
+		HolidaysFile: appCfg.HolidaysFile,
+		TimeZone: appCfg.TimeZone,
+		TrashRetention: appCfg.TrashRetention,
 	}
 
 	// Create & config server
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+)
+
+// runMigrate - CLI mode "migrate status|up" for database schema migrations
+func runMigrate(args []string, dbPath string) error {
+	if len(args) != 1 || (args[0] != "status" && args[0] != "up") {
+		return errors.New("usage: migrate status|up")
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if args[0] == "up" {
+		applied, err := database.Migrate()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migrations\n", applied)
+		return nil
+	}
+
+	statuses, err := database.MigrationStatus()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, status := range statuses {
+		appliedAt := status.AppliedAt
+		if appliedAt == "" {
+			appliedAt = "pending"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	return w.Flush()
+}
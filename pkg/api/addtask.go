@@ -4,15 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
 )
 
-func checkDate(task *db.Task) error {
-	now := time.Now()
+// checkDate validates the task date and repeat rule, "today" is the calendar day of now
+func checkDate(task *db.Task, now time.Time) error {
 	today := now.Format(dateFormat)
 
 	if task.Date == "" {
@@ -25,45 +24,37 @@ func checkDate(task *db.Task) error {
 	}
 
 	if task.Repeat != "" {
-		parts := strings.Fields(task.Repeat)
-		if len(parts) == 0 {
-			return errors.New("invalid format repeat rule")
+		rule, err := repeat.Parse(task.Repeat)
+		if err != nil {
+			return err
 		}
 
-		// Check dayli rule
-		if parts[0] == "d" {
-			if len(parts) != 2 {
-				return errors.New("invalid format dayli rule")
-			}
-
-			// Parse days interval
-			interval, err := strconv.Atoi(parts[1])
-			if err != nil {
-				return errors.New("invalid days interval")
-			}
-
-			if interval <= 0 {
-				return errors.New("interval must be more 0")
-			}
-
-			if interval > 400 {
-				return errors.New("interval must be less 400")
-			}
+		// Occurrences are counted from the task date like in /api/nextdate,
+		// a future task date may be the last occurrence of the rule
+		occurrences := repeat.NewIterator(rule, now, t)
+		next, err := occurrences.Next()
+		if err != nil && (afterNow(now, t) || !errors.Is(err, repeat.ErrNoMoreOccurrences)) {
+			return &repeat.RuleError{Code: repeat.CodeNoOccurrences, Message: err.Error(), Token: task.Repeat}
 		}
 
-		next, err := NextDate(now, task.Date, task.Repeat)
-		if err != nil {
-			return err
+		task.Remaining = initialRemaining(rule)
+		if afterNow(now, t) {
+			task.Date = next.Format(dateFormat)
+			task.Remaining = max(occurrences.Left(), 0)
 		}
+		task.StartDate = t.Format(dateFormat)
 
-		if afterNow(now, t) {
-			task.Date = next
+		task.RepeatMode, err = repeat.ParseMode(task.RepeatMode)
+		if err != nil {
+			return err
 		}
 	} else {
 		// If task not repeatable, check if it's in the past
 		if afterNow(now, t) {
 			task.Date = today
 		}
+		task.RepeatMode = repeat.ModeFixed
+		task.StartDate = ""
 	}
 
 	return nil
@@ -80,7 +71,17 @@ func AddTask(w http.ResponseWriter, r *http.Request, database *db.DB) {
 		writeJSONError(w, "Title is required", http.StatusBadRequest)
 		return
 	}
-	if err := checkDate(&task); err != nil {
+	now, err := requestNow(r, task.TimeZone)
+	if err != nil {
+		writeJSONError(w, "Invalid time zone", http.StatusBadRequest)
+		return
+	}
+	if err := checkDate(&task, now); err != nil {
+		var ruleErr *repeat.RuleError
+		if errors.As(err, &ruleErr) {
+			writeRuleError(w, err)
+			return
+		}
 		writeJSONError(w, "Invalid date", http.StatusBadRequest)
 		return
 	}
@@ -93,6 +94,14 @@ func AddTask(w http.ResponseWriter, r *http.Request, database *db.DB) {
 	writeJSONSuccess(w, map[string]int64{"id": id})
 }
 
+// initialRemaining returns occurrences left after the task date for count limited rules
+func initialRemaining(rule repeat.Rule) int {
+	if count := repeat.OccurrenceCount(rule); count > 0 {
+		return count - 1
+	}
+	return 0
+}
+
 // afterNow checks if a date is strictly after another date (ignoring time)
 func afterNow(date, now time.Time) bool {
 	// Compare only dates (year, month, day)
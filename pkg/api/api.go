@@ -4,17 +4,29 @@ import (
 	"net/http"
 
 	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
 )
 
 const (
-	dateFormat = "20060102"
+	dateFormat = repeat.DateFormat
 	limit = 50
+	// maxNextDates limits the number of dates returned by /api/nextdate
+	maxNextDates = 100
+	// maxRangeDates limits the number of dates within a range returned by /api/nextdate
+	maxRangeDates = 1000
 )
 
 // Init initializes the API routes and handlers
 func Init(router *http.ServeMux, database *db.DB) {
 	router.HandleFunc("/api/signin", SignInHandler)
 	router.HandleFunc("/api/nextdate", nextDayHandler)
+	router.HandleFunc("/api/repeat/describe", describeHandler)
+	router.HandleFunc("/api/repeat/validate", validateHandler)
+	router.HandleFunc("/api/repeat/types", ruleTypesHandler)
+	router.HandleFunc("/api/repeat/calendar", calendarHandler)
+	router.HandleFunc("/api/repeat/presets", AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		PresetsHandler(w, r, database)
+	}))
 	router.HandleFunc("/api/task", AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		TaskHandler(w, r, database)
 	}))
@@ -24,6 +36,16 @@ func Init(router *http.ServeMux, database *db.DB) {
 	router.HandleFunc("/api/task/done", AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		TaskDoneHandler(w, r, database)
 	}))
+	router.HandleFunc("/api/task/quick", AuthMiddleware(quickAddHandler))
+	router.HandleFunc("/api/task/restore", AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		RestoreTaskHandler(w, r, database)
+	}))
+	router.HandleFunc("/api/trash", AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		trashHandler(w, r, database)
+	}))
+	router.HandleFunc("/api/task/exceptions", AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ExceptionsHandler(w, r, database)
+	}))
 }
 
 // TaskHandler handle requests to /api/task 
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/calendar"
+)
+
+// workCalendar - holidays calendar of the server, nil if only weekends are days off
+var workCalendar *calendar.Calendar
+
+// SetWorkCalendar sets the holidays calendar given to the browser module of repeat rules
+func SetWorkCalendar(holidays *calendar.Calendar) {
+	workCalendar = holidays
+}
+
+// CalendarResponse - server settings of repeat rules, for the browser to compute the same dates
+type CalendarResponse struct {
+	// TimeZone - IANA time zone of "today", empty for the browser time zone
+	TimeZone string   `json:"time_zone"`
+	Holidays []string `json:"holidays"`
+	Workdays []string `json:"workdays"`
+}
+
+// calendarHandler handles GET requests to /api/repeat/calendar
+func calendarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := CalendarResponse{Holidays: []string{}, Workdays: []string{}}
+	if serverLocation != time.Local {
+		response.TimeZone = serverLocation.String()
+	}
+	if workCalendar != nil {
+		holidays, workdays := workCalendar.Dates()
+		response.Holidays = append(response.Holidays, holidays...)
+		response.Workdays = append(response.Workdays, workdays...)
+	}
+	writeJSONSuccess(w, response)
+}
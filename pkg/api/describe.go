@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
+)
+
+// DescribeResponse - struct for API response
+type DescribeResponse struct {
+	Description string `json:"description"`
+}
+
+// describeHandler handles GET requests to /api/repeat/describe
+func describeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rule := r.URL.Query().Get("repeat")
+	if rule == "" {
+		writeJSONError(w, "Repeat parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	parsedRule, err := repeat.Parse(rule)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	description, err := repeat.Describe(parsedRule, requestLang(r))
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSONSuccess(w, DescribeResponse{Description: description})
+}
+
+// RuleTypesResponse - struct for API response
+type RuleTypesResponse struct {
+	Types []string `json:"types"`
+}
+
+// ruleTypesHandler handles GET requests to /api/repeat/types, listing registered rule types
+func ruleTypesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSONSuccess(w, RuleTypesResponse{Types: repeat.RuleTypes()})
+}
+
+// requestLang gets description language from query string, Russian by default
+func requestLang(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+	return repeat.LangRU
+}
+
+// describeRule describes the repeat rule, empty for invalid rules
+func describeRule(rule, lang string) string {
+	if rule == "" {
+		return ""
+	}
+	parsedRule, err := repeat.Parse(rule)
+	if err != nil {
+		return ""
+	}
+	description, err := repeat.Describe(parsedRule, lang)
+	if err != nil {
+		return ""
+	}
+	return description
+}
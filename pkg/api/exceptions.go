@@ -0,0 +1,209 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
+)
+
+// ExceptionRequest - request to skip or move task occurrence
+type ExceptionRequest struct {
+	ID      string `json:"id"`
+	Date    string `json:"date"`
+	NewDate string `json:"new_date"`
+}
+
+// ExceptionsResponse - struct for API response
+type ExceptionsResponse struct {
+	Exceptions []db.Exception `json:"exceptions"`
+}
+
+// ExceptionsHandler handle requests to /api/task/exceptions
+func ExceptionsHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	switch r.Method {
+	case http.MethodGet:
+		getExceptionsHandler(w, r, database)
+	case http.MethodPost:
+		addExceptionHandler(w, r, database)
+	case http.MethodDelete:
+		deleteExceptionHandler(w, r, database)
+	default:
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getExceptionsHandler lists exceptions of the task
+func getExceptionsHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		writeJSONError(w, "Invalid ID parameter", http.StatusBadRequest)
+		return
+	}
+	if !checkTaskExists(w, database, id) {
+		return
+	}
+
+	exceptions, err := database.GetExceptions(id)
+	if err != nil {
+		writeJSONError(w, "Error while getting exceptions", http.StatusInternalServerError)
+		return
+	}
+	writeJSONSuccess(w, ExceptionsResponse{Exceptions: exceptions})
+}
+
+// addExceptionHandler skips or moves an occurrence of the task
+func addExceptionHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	var req ExceptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "Invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(req.ID)
+	if err != nil {
+		writeJSONError(w, "Invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	task, err := database.GetTaskByID(id)
+	if err != nil {
+		writeJSONError(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	if task.Repeat == "" {
+		writeJSONError(w, "Task is not repeatable", http.StatusBadRequest)
+		return
+	}
+
+	date, err := time.Parse(dateFormat, req.Date)
+	if err != nil {
+		writeJSONError(w, "Invalid date format", http.StatusBadRequest)
+		return
+	}
+	if req.NewDate != "" {
+		if _, err := time.Parse(dateFormat, req.NewDate); err != nil {
+			writeJSONError(w, "Invalid new date format", http.StatusBadRequest)
+			return
+		}
+		if req.NewDate == req.Date {
+			writeJSONError(w, "New date must differ from date", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// If the current occurrence is changed, the task is moved, the exception is stored
+	// only when the next date is found
+	var nextDate string
+	if req.Date == task.Date {
+		nextDate = req.NewDate
+		if nextDate == "" {
+			parsed, err := seriesRule(task.Repeat, task.StartDate)
+			if err != nil {
+				writeJSONError(w, "Error calculating next date", http.StatusInternalServerError)
+				return
+			}
+			exceptions, err := taskExceptions(database, id)
+			if err != nil {
+				writeJSONError(w, "Error calculating next date", http.StatusInternalServerError)
+				return
+			}
+			exceptions[req.Date] = req.NewDate
+
+			next, err := repeat.WithExceptions(parsed, exceptions).Next(date, date)
+			if err != nil {
+				writeJSONError(w, "Error calculating next date", http.StatusBadRequest)
+				return
+			}
+			nextDate = next.Format(dateFormat)
+		}
+	}
+
+	exception := db.Exception{TaskID: id, Date: req.Date, NewDate: req.NewDate}
+	if err := database.MoveOccurrence(exception, nextDate); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+	writeJSONEmpty(w)
+}
+
+// deleteExceptionHandler restores an occurrence of the task
+func deleteExceptionHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		writeJSONError(w, "Invalid ID parameter", http.StatusBadRequest)
+		return
+	}
+
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		writeJSONError(w, "Date parameter is required", http.StatusBadRequest)
+		return
+	}
+	if !checkTaskExists(w, database, id) {
+		return
+	}
+
+	if err := database.DeleteException(id, date); err != nil {
+		writeJSONError(w, "Exception not found", http.StatusNotFound)
+		return
+	}
+	writeJSONEmpty(w)
+}
+
+// checkTaskExists writes not found error for a task which doesn't exist or is in the trash
+func checkTaskExists(w http.ResponseWriter, database *db.DB, id int) bool {
+	if _, err := database.GetTaskByID(id); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			writeJSONError(w, "Task not found", http.StatusNotFound)
+		} else {
+			writeJSONError(w, "Getting task error", http.StatusInternalServerError)
+		}
+		return false
+	}
+	return true
+}
+
+// taskRule parses the repeat rule of the task with its series start date and exceptions
+func taskRule(database *db.DB, id int, rule, startDate string) (repeat.Rule, error) {
+	parsed, err := seriesRule(rule, startDate)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := taskExceptions(database, id)
+	if err != nil {
+		return nil, err
+	}
+	return repeat.WithExceptions(parsed, exceptions), nil
+}
+
+// seriesRule parses the repeat rule of the series started on the date, empty if unknown
+func seriesRule(rule, startDate string) (repeat.Rule, error) {
+	parsed, err := repeat.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+	if start, err := time.Parse(dateFormat, startDate); err == nil {
+		parsed = repeat.WithStart(parsed, start)
+	}
+	return parsed, nil
+}
+
+// taskExceptions gets exceptions of the task by occurrence date
+func taskExceptions(database *db.DB, id int) (repeat.Exceptions, error) {
+	exceptions, err := database.GetExceptions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make(repeat.Exceptions, len(exceptions))
+	for _, exception := range exceptions {
+		dates[exception.Date] = exception.NewDate
+	}
+	return dates, nil
+}
@@ -40,6 +40,15 @@ func GetTaskHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
 		"comment": task.Comment,
 		"repeat":  task.Repeat,
 	}
+	if task.Remaining > 0 {
+		response["remaining"] = strconv.Itoa(task.Remaining)
+	}
+	if task.Repeat != "" {
+		response["repeat_mode"] = task.RepeatMode
+	}
+	if task.TimeZone != "" {
+		response["timezone"] = task.TimeZone
+	}
 	
 	writeJSONSuccess(w, response)
 }
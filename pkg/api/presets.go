@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
+)
+
+// errPresetInUse is returned when tasks referring to a preset can't follow its new rule
+var errPresetInUse = errors.New("preset is used by tasks")
+
+// PresetResponse - named repeat rule, built-in aliases can't be changed
+type PresetResponse struct {
+	Name    string `json:"name"`
+	Repeat  string `json:"repeat"`
+	Builtin bool   `json:"builtin,omitempty"`
+}
+
+// PresetsResponse - struct for API response
+type PresetsResponse struct {
+	Presets []PresetResponse `json:"presets"`
+}
+
+// LoadPresets makes user-defined presets from DB available to repeat rules
+func LoadPresets(database *db.DB) error {
+	presets, err := database.GetPresets()
+	if err != nil {
+		return err
+	}
+
+	named := make(map[string]string, len(presets))
+	for _, preset := range presets {
+		named[preset.Name] = preset.Repeat
+	}
+	repeat.SetPresets(named)
+	return nil
+}
+
+// PresetsHandler handle requests to /api/repeat/presets
+func PresetsHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	switch r.Method {
+	case http.MethodGet:
+		getPresetsHandler(w, database)
+	case http.MethodPost:
+		setPresetHandler(w, r, database)
+	case http.MethodDelete:
+		deletePresetHandler(w, r, database)
+	default:
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getPresetsHandler lists built-in aliases followed by user-defined presets
+func getPresetsHandler(w http.ResponseWriter, database *db.DB) {
+	presets, err := database.GetPresets()
+	if err != nil {
+		writeJSONError(w, "Error while getting presets", http.StatusInternalServerError)
+		return
+	}
+
+	aliases := repeat.Aliases()
+	response := PresetsResponse{Presets: make([]PresetResponse, 0, len(aliases)+len(presets))}
+	for _, name := range slices.Sorted(maps.Keys(aliases)) {
+		response.Presets = append(response.Presets, PresetResponse{Name: name, Repeat: aliases[name], Builtin: true})
+	}
+	for _, preset := range presets {
+		response.Presets = append(response.Presets, PresetResponse{Name: preset.Name, Repeat: preset.Repeat})
+	}
+	writeJSONSuccess(w, response)
+}
+
+// setPresetHandler adds a preset or changes the rule of existing one
+func setPresetHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	var preset db.Preset
+	if err := json.NewDecoder(r.Body).Decode(&preset); err != nil {
+		writeJSONError(w, "JSON decode error", http.StatusBadRequest)
+		return
+	}
+
+	if err := repeat.ValidatePreset(preset.Name, preset.Repeat); err != nil {
+		var ruleErr *repeat.RuleError
+		if errors.As(err, &ruleErr) {
+			writeRuleError(w, err)
+			return
+		}
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Tasks referring to the preset follow its new rule
+	tasks, err := presetTasks(database, preset.Name)
+	if err != nil {
+		writeJSONError(w, "Saving preset error", http.StatusInternalServerError)
+		return
+	}
+	rescheduled, err := reschedulePresetTasks(database, preset, tasks)
+	if errors.Is(err, errPresetInUse) {
+		writeJSONError(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeJSONError(w, "Saving preset error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := database.SetPreset(preset, rescheduled); err != nil {
+		writeJSONError(w, "Saving preset error", http.StatusInternalServerError)
+		return
+	}
+	if err := LoadPresets(database); err != nil {
+		writeJSONError(w, "Loading presets error", http.StatusInternalServerError)
+		return
+	}
+	writeJSONEmpty(w)
+}
+
+// deletePresetHandler deletes a preset which is not used by tasks
+func deletePresetHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeJSONError(w, "Name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := presetTasks(database, name)
+	if err != nil {
+		writeJSONError(w, "Deleting preset error", http.StatusInternalServerError)
+		return
+	}
+	if len(tasks) > 0 {
+		writeJSONError(w, "Preset is used by tasks", http.StatusConflict)
+		return
+	}
+
+	if err := database.DeletePreset(name); err != nil {
+		writeJSONError(w, "Preset not found", http.StatusNotFound)
+		return
+	}
+	if err := LoadPresets(database); err != nil {
+		writeJSONError(w, "Loading presets error", http.StatusInternalServerError)
+		return
+	}
+	writeJSONEmpty(w)
+}
+
+// presetTasks gets tasks referring to the preset, including tasks in the trash
+func presetTasks(database *db.DB, name string) ([]db.Task, error) {
+	candidates, err := database.PresetTasks(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]db.Task, 0, len(candidates))
+	for _, task := range candidates {
+		if repeat.RefersTo(task.Repeat, name) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// reschedulePresetTasks checks the new rule of the preset against the tasks referring to it
+// and returns the tasks not in the trash with their dates under the new rule.
+// Remaining occurrences were counted with the old rule, so the count of a used preset can't be changed.
+func reschedulePresetTasks(database *db.DB, preset db.Preset, tasks []db.Task) ([]db.Task, error) {
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	newRule, err := repeat.Parse(preset.Repeat)
+	if err != nil {
+		return nil, err
+	}
+	if oldRule, err := repeat.Parse(preset.Name); err == nil && repeat.OccurrenceCount(oldRule) != repeat.OccurrenceCount(newRule) {
+		return nil, fmt.Errorf("%w, its count can't be changed", errPresetInUse)
+	}
+
+	var rescheduled []db.Task
+	for _, task := range tasks {
+		rule, err := taskRule(database, task.ID, repeat.ExpandPreset(task.Repeat, preset.Name, preset.Repeat), task.StartDate)
+		var ruleErr *repeat.RuleError
+		if errors.As(err, &ruleErr) {
+			return nil, fmt.Errorf("%w, task %d: %v", errPresetInUse, task.ID, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if task.DeletedAt != "" {
+			continue
+		}
+
+		task.Date, err = presetTaskDate(rule, task)
+		if errors.Is(err, repeat.ErrNoMoreOccurrences) {
+			return nil, fmt.Errorf("%w, task %d has no next date", errPresetInUse, task.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rescheduled = append(rescheduled, task)
+	}
+	return rescheduled, nil
+}
+
+// presetTaskDate returns the first occurrence of the rule on or after the task date in the series
+// started on the start date of the task. A series which hasn't moved from its start date yet keeps it,
+// like a new task does, and so do relative tasks, which are counted from completion.
+func presetTaskDate(rule repeat.Rule, task db.Task) (string, error) {
+	date, err := time.Parse(dateFormat, task.Date)
+	if err != nil {
+		return "", err
+	}
+	start, err := time.Parse(dateFormat, task.StartDate)
+	if err != nil || !start.Before(date) || task.RepeatMode == repeat.ModeRelative {
+		return task.Date, nil
+	}
+
+	next, err := rule.Next(date.AddDate(0, 0, -1), start)
+	if err != nil {
+		return "", err
+	}
+	return next.Format(dateFormat), nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/quickadd"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
+)
+
+// QuickAddRequest - struct for quick add request
+type QuickAddRequest struct {
+	Text string `json:"text"`
+}
+
+// quickAddHandler handles POST requests to /api/task/quick.
+// The task is not saved, it is returned for confirmation and then added by POST /api/task.
+func quickAddHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req QuickAddRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "JSON decode error", http.StatusBadRequest)
+		return
+	}
+	if req.Text == "" {
+		writeJSONError(w, "Text is required", http.StatusBadRequest)
+		return
+	}
+
+	now, err := requestNow(r, "")
+	if err != nil {
+		writeJSONError(w, "Invalid time zone", http.StatusBadRequest)
+		return
+	}
+
+	task, err := quickadd.Parse(req.Text, now)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := checkDate(&task, now); err != nil {
+		var ruleErr *repeat.RuleError
+		if errors.As(err, &ruleErr) {
+			writeRuleError(w, err)
+			return
+		}
+		writeJSONError(w, "Invalid date", http.StatusBadRequest)
+		return
+	}
+
+	response := convertTask(task, requestLang(r))
+	response.ID = ""
+	writeJSONSuccess(w, response)
+}
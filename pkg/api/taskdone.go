@@ -1,12 +1,13 @@
 package api
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
 )
 
 // TaskDoneHandler handles the task done endpoint
@@ -33,7 +34,7 @@ func TaskDoneHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
 	// Get task from DB
 	task, err := database.GetTaskByID(id)
 	if err != nil {
-		if err.Error() == "Task not found" {
+		if errors.Is(err, db.ErrNotFound) {
 			writeJSONError(w, "Task not found", http.StatusNotFound)
 			return
 		} else {
@@ -44,28 +45,109 @@ func TaskDoneHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
 
 	// If task is not repeatable, delete it
 	if task.Repeat == "" {
-		if err := database.DeleteTask(id); err != nil {
-			writeJSONError(w, "Deleting task error", http.StatusInternalServerError)
-			return
-		}
-		// Return empty response
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{})
+		finishTask(w, database, id)
+		return
+	}
+
+	rule, err := taskRule(database, id, task.Repeat, task.StartDate)
+	if err != nil {
+		writeJSONError(w, "Error calculating next date", http.StatusInternalServerError)
+		return
+	}
+
+	// If the last of count limited occurrences is done, delete it
+	if repeat.OccurrenceCount(rule) > 0 && task.Remaining <= 0 {
+		finishTask(w, database, id)
 		return
 	}
 
 	// If task is repeatable, update it
-	now := time.Now()
-	nextDate, err := NextDate(now, task.Date, task.Repeat)
+	now, err := requestNow(r, task.TimeZone)
+	if err != nil {
+		writeJSONError(w, "Invalid time zone", http.StatusBadRequest)
+		return
+	}
+	date, err := time.Parse(dateFormat, task.Date)
 	if err != nil {
 		writeJSONError(w, "Error calculating next date", http.StatusInternalServerError)
 		return
 	}
 
+	// Habit task stays active until the quota of the period is met
+	if _, ok := repeat.PeriodQuota(rule, date); ok {
+		habitDone(w, database, id, rule, now, date)
+		return
+	}
+
+	// A moved occurrence is counted from its original date to keep the series in place
+	nextDate, err := rule.Next(now, repeat.CountFrom(task.RepeatMode, now, repeat.SeriesDate(rule, date)))
+	if errors.Is(err, repeat.ErrNoMoreOccurrences) {
+		finishTask(w, database, id)
+		return
+	}
+	if err != nil {
+		writeJSONError(w, "Error calculating next date", http.StatusInternalServerError)
+		return
+	}
+
+	remaining := task.Remaining
+	if remaining > 0 {
+		remaining--
+	}
+
 	// Update task date
-	if err := database.UpdateTaskDate(id, nextDate); err != nil {
+	if err := database.RescheduleTask(id, nextDate.Format(dateFormat), remaining); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+	writeJSONEmpty(w)
+}
+
+// habitDone counts completion of habit task in the current period and moves it to the next day,
+// or to the start of the next period when the quota is met
+func habitDone(w http.ResponseWriter, database *db.DB, id int, rule repeat.Rule, now, date time.Time) {
+	quota, _ := repeat.PeriodQuota(rule, now)
+	done, err := database.AddCompletion(id, quota.Start.Format(dateFormat))
+	if err != nil {
 		writeJSONError(w, "Updating task error", http.StatusInternalServerError)
 		return
 	}
+
+	from := date
+	if done >= quota.Times && afterNow(quota.End, date) {
+		from = quota.End
+	}
+	nextDate, err := rule.Next(now, from)
+	if err != nil {
+		writeJSONError(w, "Error calculating next date", http.StatusInternalServerError)
+		return
+	}
+
+	if err := database.UpdateTaskDate(id, nextDate.Format(dateFormat)); err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+	writeJSONEmpty(w)
+}
+
+// finishTask moves the task which has no more occurrences to the trash
+func finishTask(w http.ResponseWriter, database *db.DB, id int) {
+	if err := database.DeleteTask(id); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			writeJSONError(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		writeJSONError(w, "Deleting task error", http.StatusInternalServerError)
+		return
+	}
 	writeJSONEmpty(w)
-}
\ No newline at end of file
+}
+
+// writeUpdateError writes an error of updating the task, which may have been moved to the trash meanwhile
+func writeUpdateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, db.ErrNotFound) {
+		writeJSONError(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	writeJSONError(w, "Updating task error", http.StatusInternalServerError)
+}
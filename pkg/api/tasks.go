@@ -1,10 +1,12 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
 )
 
 // TaskResponse - struct for API response
@@ -14,6 +16,18 @@ type TaskResponse struct {
 	Title   string `json:"title,omitempty"`
 	Comment string `json:"comment,omitempty"`
 	Repeat  string `json:"repeat,omitempty"`
+	// Remaining - occurrences left for count limited rules
+	Remaining string `json:"remaining,omitempty"`
+	// Description - human-readable repeat rule
+	Description string `json:"description,omitempty"`
+	// TimeZone - IANA time zone of the task
+	TimeZone string `json:"timezone,omitempty"`
+	// RepeatMode - fixed or relative, for repeating tasks only
+	RepeatMode string `json:"repeat_mode,omitempty"`
+	// Progress - completions of habit task in the current period, e.g. "1/3"
+	Progress string `json:"progress,omitempty"`
+	// DeletedAt - time the task was moved to the trash, for deleted tasks only
+	DeletedAt string `json:"deleted_at,omitempty"`
 }
 
 // TasksResponse - struct for API response
@@ -27,14 +41,52 @@ type ErrorResponse struct {
 }
 
 // convertTask - convert task from DB to API format
-func convertTask(task db.Task) TaskResponse {
-	return TaskResponse{
-		ID:      strconv.Itoa(task.ID),
-		Date:    task.Date,
-		Title:   task.Title,
-		Comment: task.Comment,
-		Repeat:  task.Repeat,
+func convertTask(task db.Task, lang string) TaskResponse {
+	response := TaskResponse{
+		ID:        strconv.Itoa(task.ID),
+		Date:      task.Date,
+		Title:     task.Title,
+		Comment:   task.Comment,
+		Repeat:    task.Repeat,
+		TimeZone:  task.TimeZone,
+		DeletedAt: task.DeletedAt,
 	}
+	if task.Remaining > 0 {
+		response.Remaining = strconv.Itoa(task.Remaining)
+	}
+	if task.Repeat != "" {
+		response.RepeatMode = task.RepeatMode
+	}
+	response.Description = describeRule(task.Repeat, lang)
+	return response
+}
+
+// habitProgress fills progress of habit task in the current period.
+// It returns false if the quota is met and the task is hidden until the next period.
+func habitProgress(r *http.Request, database *db.DB, task db.Task, response *TaskResponse) (bool, error) {
+	if task.Repeat == "" {
+		return true, nil
+	}
+	rule, err := repeat.Parse(task.Repeat)
+	if err != nil {
+		return true, nil
+	}
+
+	now, err := requestNow(r, task.TimeZone)
+	if err != nil {
+		return false, err
+	}
+	quota, ok := repeat.PeriodQuota(rule, now)
+	if !ok {
+		return true, nil
+	}
+
+	done, err := database.CompletionCount(task.ID, quota.Start.Format(dateFormat))
+	if err != nil {
+		return false, err
+	}
+	response.Progress = fmt.Sprintf("%d/%d", done, quota.Times)
+	return done < quota.Times, nil
 }
 
 // tasksHandler - handler for GET /api/tasks (без поиска)
@@ -45,6 +97,12 @@ func tasksHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
 		return
 	}
 
+	// Habit progress is counted in the request time zone, which must be known
+	if _, err := requestNow(r, ""); err != nil {
+		writeJSONError(w, "Invalid time zone", http.StatusBadRequest)
+		return
+	}
+
 	// Getting tasks from database with limit 50
 	tasks, err := database.GetAllTasks(limit)
 	if err != nil {
@@ -53,9 +111,18 @@ func tasksHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
 	}
 
 	// Convert tasks to API format
+	lang := requestLang(r)
 	taskResponse := make([]TaskResponse, 0, len(tasks))
 	for _, task := range tasks {
-		taskResponse = append(taskResponse, convertTask(task))
+		response := convertTask(task, lang)
+		active, err := habitProgress(r, database, task, &response)
+		if err != nil {
+			writeJSONError(w, "Error while getting tasks", http.StatusInternalServerError)
+			return
+		}
+		if active {
+			taskResponse = append(taskResponse, response)
+		}
 	}
 
 	// Create response
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// serverLocation - time zone of the calendar day for tasks without their own zone
+var serverLocation = time.Local
+
+// SetTimeZone sets the server time zone
+func SetTimeZone(loc *time.Location) {
+	serverLocation = loc
+}
+
+// loadLocation loads IANA time zone, empty name gives the server time zone
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return serverLocation, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("unknown time zone " + name)
+	}
+	return loc, nil
+}
+
+// requestNow returns current time in the time zone from 'tz' parameter,
+// the task time zone or the server time zone, whichever is set first
+func requestNow(r *http.Request, taskZone string) (time.Time, error) {
+	loc, err := loadLocation(taskZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if name := r.URL.Query().Get("tz"); name != "" {
+		if loc, err = loadLocation(name); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Now().In(loc), nil
+}
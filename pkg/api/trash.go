@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+)
+
+// trashHandler - handler for GET /api/trash, lists deleted tasks until they are purged
+func trashHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tasks, err := database.GetTrash(limit)
+	if err != nil {
+		writeJSONError(w, "Error while getting trash", http.StatusInternalServerError)
+		return
+	}
+
+	lang := requestLang(r)
+	response := TasksResponse{Tasks: make([]TaskResponse, 0, len(tasks))}
+	for _, task := range tasks {
+		response.Tasks = append(response.Tasks, convertTask(task, lang))
+	}
+	writeJSONSuccess(w, response)
+}
+
+// RestoreTaskHandler handles POST /api/task/restore, moving the task back from the trash
+func RestoreTaskHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := r.URL.Query().Get("id")
+	if idStr == "" {
+		writeJSONError(w, "ID parameter is required", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		writeJSONError(w, "Invalid ID parameter", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.RestoreTask(id); err != nil {
+		writeJSONError(w, "Task not found in trash", http.StatusNotFound)
+		return
+	}
+	writeJSONEmpty(w)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
 )
 
 type UpdateTaskRequest struct {
@@ -15,6 +16,10 @@ type UpdateTaskRequest struct {
 	Title string `json:"title"`
 	Comment string `json:"comment"`
 	Repeat string `json:"repeat"`
+	// TimeZone - nil keeps the zone of the task
+	TimeZone *string `json:"timezone"`
+	// RepeatMode - nil keeps the mode of the task
+	RepeatMode *string `json:"repeat_mode"`
 }
 
 func UpdateTaskHandler(w http.ResponseWriter, r *http.Request, database *db.DB) {
@@ -50,13 +55,24 @@ func UpdateTaskHandler(w http.ResponseWriter, r *http.Request, database *db.DB)
 	}
 
 	// Check task existing
-	if _, err := database.GetTaskByID(id); err != nil {
+	existing, err := database.GetTaskByID(id)
+	if err != nil {
 		writeJSONError(w, "Task not found", http.StatusNotFound)
 		return
 	}
 	
+	// Keep the time zone unless it is sent
+	timeZone := existing.TimeZone
+	if req.TimeZone != nil {
+		timeZone = *req.TimeZone
+	}
+
 	//Format date
-	now := time.Now()
+	now, err := requestNow(r, timeZone)
+	if err != nil {
+		writeJSONError(w, "Invalid time zone", http.StatusBadRequest)
+		return
+	}
 	today := now.Format(dateFormat)
 
 	dateToUse := req.Date
@@ -72,15 +88,43 @@ func UpdateTaskHandler(w http.ResponseWriter, r *http.Request, database *db.DB)
 	}
 
 	// Check repeat rules
+	remaining := 0
+	startDate := ""
+	mode := existing.RepeatMode
+	if req.RepeatMode != nil {
+		mode = *req.RepeatMode
+	}
+	mode, err = repeat.ParseMode(mode)
+	if err != nil {
+		writeJSONError(w, "Invalid repeat mode", http.StatusBadRequest)
+		return
+	}
 	if req.Repeat != "" {
-		nextDate, err := NextDate(now, dateToUse, req.Repeat)
+		// The series starts anew when the rule or the date is changed
+		startDate = existing.StartDate
+		if req.Repeat != existing.Repeat || dateToUse != existing.Date {
+			startDate = dateToUse
+		}
+
+		rule, err := taskRule(database, id, req.Repeat, startDate)
+		if err != nil {
+			writeRuleError(w, err)
+			return
+		}
+		nextDate, err := rule.Next(now, parsedDate)
 		if err != nil {
-			writeJSONError(w, "Invalid repeat rule", http.StatusBadRequest)
+			writeRuleError(w, &repeat.RuleError{Code: repeat.CodeNoOccurrences, Message: err.Error(), Token: req.Repeat})
 			return
 		}
-		dateToUse = nextDate
+		dateToUse = nextDate.Format(dateFormat)
+
+		// Keep occurrences count unless the rule is changed
+		remaining = existing.Remaining
+		if req.Repeat != existing.Repeat {
+			remaining = initialRemaining(rule)
+		}
 	} else {
-		if parsedDate.Before(now) {
+		if afterNow(now, parsedDate) {
 			dateToUse = today
 		}
 	}
@@ -91,6 +135,10 @@ func UpdateTaskHandler(w http.ResponseWriter, r *http.Request, database *db.DB)
 		Title: req.Title,
 		Comment: req.Comment,
 		Repeat: req.Repeat,
+		Remaining: remaining,
+		TimeZone: timeZone,
+		RepeatMode: mode,
+		StartDate: startDate,
 	}
 
 	// Update task in BD
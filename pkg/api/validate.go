@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
+)
+
+// ValidateRequest - struct for repeat rule validation request
+type ValidateRequest struct {
+	Repeat string `json:"repeat"`
+}
+
+// RuleErrorResponse - struct for repeat rule error, the offset is in characters
+type RuleErrorResponse struct {
+	Error  string `json:"error"`
+	Code   string `json:"code"`
+	Token  string `json:"token"`
+	Offset int    `json:"offset"`
+}
+
+// ValidateResponse - struct for API response, error fields are set for invalid rules only
+type ValidateResponse struct {
+	Valid bool `json:"valid"`
+	*RuleErrorResponse
+}
+
+// validateHandler handles POST requests to /api/repeat/validate
+func validateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "JSON decode error", http.StatusBadRequest)
+		return
+	}
+
+	now, err := requestNow(r, "")
+	if err != nil {
+		writeJSONError(w, "Invalid time zone", http.StatusBadRequest)
+		return
+	}
+
+	if err := repeat.Validate(req.Repeat, now); err != nil {
+		writeJSONSuccess(w, ValidateResponse{RuleErrorResponse: ruleErrorResponse(err)})
+		return
+	}
+	writeJSONSuccess(w, ValidateResponse{Valid: true})
+}
+
+// ruleErrorResponse converts an error to API format, pointing to the whole rule for untyped errors
+func ruleErrorResponse(err error) *RuleErrorResponse {
+	var ruleErr *repeat.RuleError
+	if !errors.As(err, &ruleErr) {
+		return &RuleErrorResponse{Error: err.Error(), Code: repeat.CodeValue}
+	}
+	return &RuleErrorResponse{
+		Error:  ruleErr.Message,
+		Code:   ruleErr.Code,
+		Token:  ruleErr.Token,
+		Offset: ruleErr.Offset,
+	}
+}
+
+// writeRuleError writes a repeat rule error with its position in JSON format
+func writeRuleError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(ruleErrorResponse(err))
+}
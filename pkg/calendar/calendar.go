@@ -0,0 +1,105 @@
+// Package calendar loads a working days calendar with public holidays
+// and transferred working days, e.g. the Russian production calendar.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const dateFormat = "20060102"
+
+// Calendar - working days calendar
+type Calendar struct {
+	// holidays - non-working days falling on weekdays
+	holidays map[string]bool
+	// workdays - working days falling on weekends
+	workdays map[string]bool
+}
+
+// New creates calendar where only weekends are non-working days
+func New() *Calendar {
+	return &Calendar{
+		holidays: map[string]bool{},
+		workdays: map[string]bool{},
+	}
+}
+
+// Load reads calendar from file. Every line holds a date of a holiday,
+// or a date followed by "workday" for a working weekend day.
+// Empty lines and lines starting with '#' are ignored.
+func Load(path string) (*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	calendar := New()
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if _, err := time.Parse(dateFormat, fields[0]); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid date %q", path, lineNumber, fields[0])
+		}
+
+		switch {
+		case len(fields) == 1:
+			calendar.holidays[fields[0]] = true
+		case len(fields) == 2 && fields[1] == "workday":
+			calendar.workdays[fields[0]] = true
+		default:
+			return nil, fmt.Errorf("%s:%d: invalid line %q", path, lineNumber, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// FromDates creates calendar with holidays falling on weekdays
+// and working days falling on weekends, dates are in "20060102" format
+func FromDates(holidays, workdays []string) (*Calendar, error) {
+	calendar := New()
+	for _, dates := range []struct {
+		list []string
+		set  map[string]bool
+	}{{holidays, calendar.holidays}, {workdays, calendar.workdays}} {
+		for _, date := range dates.list {
+			if _, err := time.Parse(dateFormat, date); err != nil {
+				return nil, fmt.Errorf("invalid date %q", date)
+			}
+			dates.set[date] = true
+		}
+	}
+	return calendar, nil
+}
+
+// Dates returns holidays and working weekend days of the calendar in date order
+func (c *Calendar) Dates() (holidays, workdays []string) {
+	return slices.Sorted(maps.Keys(c.holidays)), slices.Sorted(maps.Keys(c.workdays))
+}
+
+// IsWorkday checks if the date is a working day
+func (c *Calendar) IsWorkday(date time.Time) bool {
+	key := date.Format(dateFormat)
+	if c.workdays[key] {
+		return true
+	}
+	if c.holidays[key] {
+		return false
+	}
+	weekday := date.Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday
+}
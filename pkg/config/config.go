@@ -0,0 +1,37 @@
+// Package config loads application settings from a YAML file
+package config
+
+import (
+	"errors"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config - application settings
+type Config struct {
+	// HolidaysFile - path to working days calendar, see calendar.Load
+	HolidaysFile string `yaml:"holidays_file"`
+	// TimeZone - IANA time zone of the server calendar day, local zone if empty
+	TimeZone string `yaml:"time_zone"`
+	// TrashRetention - how long deleted tasks are kept in the trash, Go duration such as "720h"
+	TrashRetention string `yaml:"trash_retention"`
+}
+
+// Load reads settings from file. Missing file gives default settings.
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
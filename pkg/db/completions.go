@@ -0,0 +1,20 @@
+package db
+
+// AddCompletion counts one more completion of habit task in the period and returns the period count.
+// The period is identified by its first day.
+func (d *DB) AddCompletion(taskID int, period string) (int, error) {
+	query := `INSERT INTO completions (task_id, period, count) VALUES (?, ?, 1)
+		ON CONFLICT (task_id, period) DO UPDATE SET count = count + 1
+		RETURNING count`
+	var count int
+	err := d.db.QueryRow(query, taskID, period).Scan(&count)
+	return count, err
+}
+
+// CompletionCount gets the number of completions of habit task in the period
+func (d *DB) CompletionCount(taskID int, period string) (int, error) {
+	query := `SELECT COALESCE(SUM(count), 0) FROM completions WHERE task_id = ? AND period = ?`
+	var count int
+	err := d.db.QueryRow(query, taskID, period).Scan(&count)
+	return count, err
+}
@@ -2,8 +2,9 @@ package db
 
 import (
 	"database/sql"
-	"fmt"
+	"errors"
 	"log"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -15,8 +16,21 @@ type Task struct {
 	Title   string `json:"title,omitempty"`
 	Comment string `json:"comment,omitempty"`
 	Repeat  string `json:"repeat,omitempty"`
+	// Remaining - occurrences left after the current one for count limited rules
+	Remaining int `json:"remaining,omitempty"`
+	// TimeZone - IANA time zone of the task calendar day, server zone if empty
+	TimeZone string `json:"timezone,omitempty"`
+	// RepeatMode - fixed or relative, see repeat.ModeFixed and repeat.ModeRelative
+	RepeatMode string `json:"repeat_mode,omitempty"`
+	// StartDate - date the series of repeating task started on, empty if unknown
+	StartDate string `json:"start_date,omitempty"`
+	// DeletedAt - UTC time the task was moved to the trash in RFC 3339 format, empty for active tasks
+	DeletedAt string `json:"deleted_at,omitempty"`
 }
 
+// ErrNotFound - task doesn't exist or is in the trash
+var ErrNotFound = errors.New("task not found")
+
 // DB - struct for DB connection
 type DB struct {
 	db *sql.DB
@@ -27,41 +41,38 @@ func (d *DB) Close() error {
 	return d.db.Close()
 }
 
-const schema = `
-CREATE TABLE IF NOT EXISTS scheduler (
-	id INTEGER PRIMARY KEY AUTOINCREMENT,
-	date CHAR(8) NOT NULL DEFAULT "19700101",
-	title VARCHAR(256) NOT NULL DEFAULT "",
-	comment TEXT NOT NULL DEFAULT "",
-	repeat VARCHAR(128) NOT NULL DEFAULT ""
-);
-CREATE INDEX IF NOT EXISTS idx_date_scheduler ON scheduler(date);
-`
-
-// Init - initialize DB connection
-func Init(dbFile string) (*DB, error) {
-	log.Printf("Initializing database: %s", dbFile)
-	
+// Open - open DB connection without changing the schema
+func Open(dbFile string) (*DB, error) {
 	db, err := sql.Open("sqlite", dbFile)
 	if err != nil {
 		return nil, err
 	}
+	return &DB{db: db}, nil
+}
+
+// Init - initialize DB connection and apply pending migrations
+func Init(dbFile string) (*DB, error) {
+	log.Printf("Initializing database: %s", dbFile)
 
-	// Всегда создаем таблицы если их нет
-	_, err = db.Exec(schema)
+	database, err := Open(dbFile)
 	if err != nil {
-		db.Close()
 		return nil, err
 	}
-	
+
+	// Всегда приводим схему к последней версии
+	if _, err := database.Migrate(); err != nil {
+		database.Close()
+		return nil, err
+	}
+
 	log.Printf("DB initialized successfully")
-	return &DB{db: db}, nil
+	return database, nil
 }
 
 // AddTask add task to database
 func (d *DB) AddTask(task Task) (int64, error) {
-	query := `INSERT INTO scheduler (date, title, comment, repeat) VALUES (?, ?, ?, ?)`
-	result, err := d.db.Exec(query, task.Date, task.Title, task.Comment, task.Repeat)
+	query := `INSERT INTO scheduler (date, title, comment, repeat, remaining, timezone, repeat_mode, start_date) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := d.db.Exec(query, task.Date, task.Title, task.Comment, task.Repeat, task.Remaining, task.TimeZone, task.RepeatMode, task.StartDate)
 	if err != nil {
 		return 0, err
 	}
@@ -71,11 +82,12 @@ func (d *DB) AddTask(task Task) (int64, error) {
 // GetTaskByID get task by id from database
 func (d *DB) GetTaskByID(id int) (Task, error) {
 	var task Task
-	query := `SELECT id, date, title, comment, repeat FROM scheduler WHERE id = ?`
-	err := d.db.QueryRow(query, id).Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat)
+	query := `SELECT id, date, title, comment, repeat, remaining, timezone, repeat_mode, start_date FROM scheduler
+		WHERE id = ? AND deleted_at = ""`
+	err := d.db.QueryRow(query, id).Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat, &task.Remaining, &task.TimeZone, &task.RepeatMode, &task.StartDate)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return Task{}, fmt.Errorf("task not found")
+			return Task{}, ErrNotFound
 		}
 		return Task{}, err
 	}
@@ -84,8 +96,9 @@ func (d *DB) GetTaskByID(id int) (Task, error) {
 
 // UpdateTask update task in database
 func (d *DB) UpdateTask(task Task) error {
-	query := `UPDATE scheduler SET date = ?, title = ?, comment = ?, repeat = ? WHERE id = ?`
-	result, err := d.db.Exec(query, task.Date, task.Title, task.Comment, task.Repeat, task.ID)
+	query := `UPDATE scheduler SET date = ?, title = ?, comment = ?, repeat = ?, remaining = ?, timezone = ?, repeat_mode = ?, start_date = ?
+		WHERE id = ? AND deleted_at = ""`
+	result, err := d.db.Exec(query, task.Date, task.Title, task.Comment, task.Repeat, task.Remaining, task.TimeZone, task.RepeatMode, task.StartDate, task.ID)
 	if err != nil {
 		return err
 	}
@@ -94,15 +107,15 @@ func (d *DB) UpdateTask(task Task) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("task not found")
+		return ErrNotFound
 	}
 	return nil
 }
 
 // GetAllTasks gets all tasks
 func (d *DB) GetAllTasks(limit int) ([]Task, error) {
-	query := `SELECT id, date, title, comment, repeat FROM scheduler
-		ORDER BY date ASC, id ASC LIMIT ?`
+	query := `SELECT id, date, title, comment, repeat, remaining, timezone, repeat_mode, start_date FROM scheduler
+		WHERE deleted_at = "" ORDER BY date ASC, id ASC LIMIT ?`
 	
 	rows, err := d.db.Query(query, limit)
 	if err != nil {
@@ -113,7 +126,7 @@ func (d *DB) GetAllTasks(limit int) ([]Task, error) {
 	var tasks []Task
 	for rows.Next() {
 		var task Task
-		if err := rows.Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat); err != nil {
+		if err := rows.Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat, &task.Remaining, &task.TimeZone, &task.RepeatMode, &task.StartDate); err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, task)
@@ -129,10 +142,10 @@ func (d *DB) GetAllTasks(limit int) ([]Task, error) {
 	return tasks, nil
 }
 
-// DeleteTask delete task by ID
+// DeleteTask move task to the trash, see RestoreTask and PurgeTrash
 func (d *DB) DeleteTask(id int) error {
-	query := `DELETE FROM scheduler WHERE id = ?`
-	result, err := d.db.Exec(query, id)
+	query := `UPDATE scheduler SET deleted_at = ? WHERE id = ? AND deleted_at = ""`
+	result, err := d.db.Exec(query, time.Now().UTC().Format(time.RFC3339), id)
 	if err != nil {
 		return err
 	}
@@ -141,14 +154,14 @@ func (d *DB) DeleteTask(id int) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("no task found with ID %d", id)
+		return ErrNotFound
 	}
 	return nil
 }
 
-// UpdateTaskDate update only task date
+// UpdateTaskDate update only task date of a task which is not in the trash
 func (d *DB) UpdateTaskDate(id int, date string) error {
-	query := `UPDATE scheduler SET date = ? WHERE id = ?`
+	query := `UPDATE scheduler SET date = ? WHERE id = ? AND deleted_at = ""`
 	result, err := d.db.Exec(query, date, id)
 	if err != nil {
 		return err
@@ -158,7 +171,24 @@ func (d *DB) UpdateTaskDate(id int, date string) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("no task found with ID %d", id)
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RescheduleTask update task date and remaining occurrences of a task which is not in the trash
+func (d *DB) RescheduleTask(id int, date string, remaining int) error {
+	query := `UPDATE scheduler SET date = ?, remaining = ? WHERE id = ? AND deleted_at = ""`
+	result, err := d.db.Exec(query, date, remaining, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
 	}
 	return nil
-}
\ No newline at end of file
+}
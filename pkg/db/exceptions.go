@@ -0,0 +1,86 @@
+package db
+
+import "fmt"
+
+// Exception - skipped or moved occurrence of a repeating task
+type Exception struct {
+	TaskID  int    `json:"-"`
+	Date    string `json:"date"`
+	NewDate string `json:"new_date,omitempty"`
+}
+
+// SetException add or replace exception for task occurrence
+func (d *DB) SetException(exception Exception) error {
+	query := `INSERT INTO exceptions (task_id, date, new_date) VALUES (?, ?, ?)
+		ON CONFLICT (task_id, date) DO UPDATE SET new_date = excluded.new_date`
+	_, err := d.db.Exec(query, exception.TaskID, exception.Date, exception.NewDate)
+	return err
+}
+
+// MoveOccurrence add or replace exception for task occurrence and move the task to the date,
+// an empty date keeps the task date. Tasks in the trash are not changed.
+func (d *DB) MoveOccurrence(exception Exception, date string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE scheduler SET date = COALESCE(NULLIF(?, ""), date) WHERE id = ? AND deleted_at = ""`
+	result, err := tx.Exec(query, date, exception.TaskID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	query = `INSERT INTO exceptions (task_id, date, new_date) VALUES (?, ?, ?)
+		ON CONFLICT (task_id, date) DO UPDATE SET new_date = excluded.new_date`
+	if _, err := tx.Exec(query, exception.TaskID, exception.Date, exception.NewDate); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetExceptions gets all exceptions of task ordered by date
+func (d *DB) GetExceptions(taskID int) ([]Exception, error) {
+	query := `SELECT task_id, date, new_date FROM exceptions WHERE task_id = ? ORDER BY date ASC`
+
+	rows, err := d.db.Query(query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exceptions := []Exception{}
+	for rows.Next() {
+		var exception Exception
+		if err := rows.Scan(&exception.TaskID, &exception.Date, &exception.NewDate); err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, exception)
+	}
+	return exceptions, rows.Err()
+}
+
+// DeleteException delete exception for task occurrence
+func (d *DB) DeleteException(taskID int, date string) error {
+	query := `DELETE FROM exceptions WHERE task_id = ? AND date = ?`
+	result, err := d.db.Exec(query, taskID, date)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no exception found for task %d on %s", taskID, date)
+	}
+	return nil
+}
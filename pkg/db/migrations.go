@@ -0,0 +1,181 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// migration - versioned schema change. Migrations are applied in order of versions,
+// each one in its own transaction together with its record in schema_migrations.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// migrations - schema history, append new migrations to the end and never change applied ones.
+// Databases created before migrations already have some of the tables and columns,
+// so the first migrations don't fail on existing ones.
+var migrations = []migration{
+	{1, "create scheduler table", execSQL(`
+		CREATE TABLE IF NOT EXISTS scheduler (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			date CHAR(8) NOT NULL DEFAULT "19700101",
+			title VARCHAR(256) NOT NULL DEFAULT "",
+			comment TEXT NOT NULL DEFAULT "",
+			repeat VARCHAR(128) NOT NULL DEFAULT ""
+		);
+		CREATE INDEX IF NOT EXISTS idx_date_scheduler ON scheduler(date);`)},
+	{2, "add remaining occurrences", addColumn("scheduler", "remaining", "INTEGER NOT NULL DEFAULT 0")},
+	{3, "create exceptions table", execSQL(`
+		CREATE TABLE IF NOT EXISTS exceptions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			task_id INTEGER NOT NULL,
+			date CHAR(8) NOT NULL,
+			new_date CHAR(8) NOT NULL DEFAULT "",
+			UNIQUE (task_id, date)
+		);`)},
+	{4, "add task time zone", addColumn("scheduler", "timezone", `VARCHAR(64) NOT NULL DEFAULT ""`)},
+	{5, "add repeat mode", addColumn("scheduler", "repeat_mode", `VARCHAR(16) NOT NULL DEFAULT "fixed"`)},
+	{6, "create completions table", execSQL(`
+		CREATE TABLE IF NOT EXISTS completions (
+			task_id INTEGER NOT NULL,
+			period CHAR(8) NOT NULL,
+			count INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (task_id, period)
+		);`)},
+	{7, "create presets table", execSQL(`
+		CREATE TABLE IF NOT EXISTS presets (
+			name VARCHAR(32) PRIMARY KEY,
+			repeat VARCHAR(128) NOT NULL DEFAULT ""
+		);`)},
+	{8, "add task deletion time", addColumn("scheduler", "deleted_at", `VARCHAR(32) NOT NULL DEFAULT ""`)},
+	{9, "add series start date", addColumn("scheduler", "start_date", `CHAR(8) NOT NULL DEFAULT ""`)},
+}
+
+// MigrationStatus - state of a migration, AppliedAt is empty for pending ones
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt string
+}
+
+// execSQL returns migration running SQL statements
+func execSQL(query string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+// addColumn returns migration adding column to table if it doesn't exist
+func addColumn(table, column, definition string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		var count int
+		query := `SELECT count(*) FROM pragma_table_info(?) WHERE name = ?`
+		if err := tx.QueryRow(query, table, column).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		_, err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+		return err
+	}
+}
+
+// createMigrationsTable creates the table of applied migrations
+func (d *DB) createMigrationsTable() error {
+	_, err := d.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(128) NOT NULL DEFAULT "",
+		applied_at VARCHAR(32) NOT NULL DEFAULT ""
+	)`)
+	return err
+}
+
+// MigrationStatus gets the state of all known migrations in order of versions
+func (d *DB) MigrationStatus() ([]MigrationStatus, error) {
+	applied, err := d.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		statuses = append(statuses, MigrationStatus{Version: m.version, Name: m.name, AppliedAt: applied[m.version]})
+	}
+	return statuses, nil
+}
+
+// appliedMigrations gets application times by versions of applied migrations.
+// A database created before migrations has none of them.
+func (d *DB) appliedMigrations() (map[int]string, error) {
+	applied := map[int]string{}
+
+	var count int
+	query := `SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`
+	if err := d.db.QueryRow(query).Scan(&count); err != nil || count == 0 {
+		return applied, err
+	}
+
+	rows, err := d.db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var appliedAt string
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// Migrate applies pending migrations and returns the number of applied ones
+func (d *DB) Migrate() (int, error) {
+	if err := d.createMigrationsTable(); err != nil {
+		return 0, err
+	}
+	statuses, err := d.MigrationStatus()
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for i, status := range statuses {
+		if status.AppliedAt != "" {
+			continue
+		}
+		if err := d.applyMigration(migrations[i]); err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %w", status.Version, status.Name, err)
+		}
+		log.Printf("Applied migration %d: %s", status.Version, status.Name)
+		applied++
+	}
+	return applied, nil
+}
+
+// applyMigration runs migration and records it in one transaction
+func (d *DB) applyMigration(m migration) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return err
+	}
+	query := `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`
+	if _, err := tx.Exec(query, m.version, m.name, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
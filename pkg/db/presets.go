@@ -0,0 +1,91 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Preset - user-defined named repeat rule
+type Preset struct {
+	Name   string `json:"name"`
+	Repeat string `json:"repeat"`
+}
+
+// SetPreset add or replace preset, moving the tasks referring to it to their new dates.
+// Tasks in the trash are not changed.
+func (d *DB) SetPreset(preset Preset, rescheduled []Task) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO presets (name, repeat) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET repeat = excluded.repeat`
+	if _, err := tx.Exec(query, preset.Name, preset.Repeat); err != nil {
+		return err
+	}
+	for _, task := range rescheduled {
+		if _, err := tx.Exec(`UPDATE scheduler SET date = ? WHERE id = ? AND deleted_at = ""`, task.Date, task.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPresets gets all presets ordered by name
+func (d *DB) GetPresets() ([]Preset, error) {
+	rows, err := d.db.Query(`SELECT name, repeat FROM presets ORDER BY name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	presets := []Preset{}
+	for rows.Next() {
+		var preset Preset
+		if err := rows.Scan(&preset.Name, &preset.Repeat); err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+	return presets, rows.Err()
+}
+
+// PresetTasks gets tasks which may refer to preset, including tasks in the trash which may be restored.
+// The rule of a task mentions the preset name, whether it is a reference is up to the caller.
+func (d *DB) PresetTasks(name string) ([]Task, error) {
+	query := `SELECT id, date, title, comment, repeat, remaining, timezone, repeat_mode, start_date, deleted_at FROM scheduler
+		WHERE instr(repeat, ?) > 0 ORDER BY id ASC`
+	rows, err := d.db.Query(query, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var task Task
+		if err := rows.Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat, &task.Remaining, &task.TimeZone, &task.RepeatMode, &task.StartDate, &task.DeletedAt); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// DeletePreset delete preset by name
+func (d *DB) DeletePreset(name string) error {
+	result, err := d.db.Exec(`DELETE FROM presets WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no preset found with name %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetTrash gets tasks moved to the trash, recently deleted first
+func (d *DB) GetTrash(limit int) ([]Task, error) {
+	query := `SELECT id, date, title, comment, repeat, remaining, timezone, repeat_mode, start_date, deleted_at FROM scheduler
+		WHERE deleted_at != "" ORDER BY deleted_at DESC, id DESC LIMIT ?`
+
+	rows, err := d.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var task Task
+		if err := rows.Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat, &task.Remaining, &task.TimeZone, &task.RepeatMode, &task.StartDate, &task.DeletedAt); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// RestoreTask move task back from the trash
+func (d *DB) RestoreTask(id int) error {
+	query := `UPDATE scheduler SET deleted_at = "" WHERE id = ? AND deleted_at != ""`
+	result, err := d.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no task found in trash with ID %d", id)
+	}
+	return nil
+}
+
+// PurgeTrash permanently delete tasks moved to the trash before the time, with their exceptions
+// and completions. It returns the number of deleted tasks.
+func (d *DB) PurgeTrash(before time.Time) (int64, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	cutoff := before.UTC().Format(time.RFC3339)
+	expired := `SELECT id FROM scheduler WHERE deleted_at != "" AND deleted_at < ?`
+	if _, err := tx.Exec(`DELETE FROM exceptions WHERE task_id IN (`+expired+`)`, cutoff); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`DELETE FROM completions WHERE task_id IN (`+expired+`)`, cutoff); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(`DELETE FROM scheduler WHERE deleted_at != "" AND deleted_at < ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return purged, tx.Commit()
+}
@@ -0,0 +1,340 @@
+// Package quickadd parses free-form task text like "Pay rent on the 5th every month"
+// or "Позвонить маме завтра" into a task with title, date and repeat rule.
+package quickadd
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
+)
+
+// Weekday and month names in English and Russian
+const (
+	enWeekday  = `monday|tuesday|wednesday|thursday|friday|saturday|sunday`
+	enWeekdays = `(?:mondays?|tuesdays?|wednesdays?|thursdays?|fridays?|saturdays?|sundays?|mon|tue|wed|thu|fri|sat|sun)`
+	ruWeekdays = `(?:понедельник\p{L}*|вторник\p{L}*|сред\p{L}*|четверг\p{L}*|пятниц\p{L}*|суббот\p{L}*|воскресень\p{L}*)`
+	enMonths   = `january|february|march|april|may|june|july|august|september|october|november|december`
+	ruMonths   = `января|февраля|марта|апреля|мая|июня|июля|августа|сентября|октября|ноября|декабря`
+)
+
+// weekdayName finds weekday names in a list of weekdays
+var weekdayName = regexp.MustCompile(`(?i)` + enWeekdays + `|` + ruWeekdays)
+
+// ruWeekdayStems are Russian weekday stems, index 0 is Sunday
+var ruWeekdayStems = []string{"воскресень", "понедельник", "вторник", "сред", "четверг", "пятниц", "суббот"}
+
+// phrase is a part of the text that sets the date or the repeat rule
+type phrase struct {
+	re    *regexp.Regexp
+	apply func(p *parser, groups []string) error
+}
+
+// newPhrase compiles a case-insensitive phrase that must be separated from the rest of the text
+func newPhrase(expr string, apply func(p *parser, groups []string) error) phrase {
+	return phrase{
+		re:    regexp.MustCompile(`(?i)(?:^|\s)(?:` + expr + `)(?:[\s,.!?]|$)`),
+		apply: apply,
+	}
+}
+
+// repeatPhrases are checked in order, the first match sets the repeat rule
+var repeatPhrases = []phrase{
+	newPhrase(`(?:on )?the (?:(\d{1,2})(?:st|nd|rd|th)?|(last)) (?:day )?(?:of )?(?:every|each) month`, monthDay),
+	newPhrase(`(?:every|each) month on the (?:(\d{1,2})(?:st|nd|rd|th)?|(last)) ?(?:day)?`, monthDay),
+	newPhrase(`(?:(\d{1,2})(?:-?го)?|в (последний)) (?:числа|день) каждого месяца`, monthDay),
+	newPhrase(`каждое (\d{1,2})(?:-?е)? число`, monthDay),
+	newPhrase(`(?:every|each) (\d+) days?|каждые (\d+) (?:дня|дней)`, everyDays(1)),
+	newPhrase(`(?:every|each) (\d+) weeks?|каждые (\d+) недел\p{L}*`, everyDays(7)),
+	newPhrase(`(?:every|each) other (day)|через (день)`, func(p *parser, _ []string) error {
+		p.repeat = "d 2"
+		return nil
+	}),
+	newPhrase(`every day|daily|каждый день|ежедневно`, fixedRule("d 1")),
+	newPhrase(`(?:every|each) (`+enWeekdays+`(?:(?:\s*,\s*|\s+and\s+|\s*,\s*and\s+)`+enWeekdays+`)*)`, weekdays),
+	newPhrase(`(?:каждый|каждую|каждое|каждые|по) (`+ruWeekdays+`(?:(?:\s*,\s*|\s+и\s+)`+ruWeekdays+`)*)`, weekdays),
+	newPhrase(`every week|weekly|каждую неделю|еженедельно`, fixedRule("d 7")),
+	newPhrase(`every month|monthly|каждый месяц|ежемесячно`, func(p *parser, _ []string) error {
+		// The day of month is known only after the date is parsed
+		p.monthly = true
+		return nil
+	}),
+	newPhrase(`every year|yearly|annually|каждый год|ежегодно`, fixedRule("y")),
+}
+
+// datePhrases are checked in order, the first match sets the task date
+var datePhrases = []phrase{
+	newPhrase(`(?:the )?day after tomorrow|послезавтра`, inDays(2)),
+	newPhrase(`today|tonight|сегодня`, inDays(0)),
+	newPhrase(`tomorrow|завтра`, inDays(1)),
+	newPhrase(`in (\d+) days?|через (\d+) (?:день|дня|дней)`, func(p *parser, groups []string) error {
+		days, err := number(groups)
+		if err != nil {
+			return err
+		}
+		return inDays(days)(p, groups)
+	}),
+	newPhrase(`in a week|через неделю`, inDays(7)),
+	newPhrase(`in (\d+) weeks?|через (\d+) недел\p{L}*`, func(p *parser, groups []string) error {
+		weeks, err := number(groups)
+		if err != nil {
+			return err
+		}
+		return inDays(weeks*7)(p, groups)
+	}),
+	newPhrase(`(?:on )?(\d{8})`, func(p *parser, groups []string) error {
+		date, err := time.Parse(repeat.DateFormat, groups[0])
+		if err != nil {
+			return errors.New("invalid date " + groups[0])
+		}
+		p.setDate(date)
+		return nil
+	}),
+	newPhrase(`(?:on )?(\d{1,2})\.(\d{1,2})\.(\d{4})`, func(p *parser, groups []string) error {
+		date, err := time.Parse("2.1.2006", strings.Join(groups, "."))
+		if err != nil {
+			return errors.New("invalid date " + strings.Join(groups, "."))
+		}
+		p.setDate(date)
+		return nil
+	}),
+	newPhrase(`(?:on )?(\d{1,2})(?:st|nd|rd|th)?(?: of)? (`+enMonths+`)`, func(p *parser, groups []string) error {
+		return p.setMonthDay(groups[0], monthNumber(enMonths, groups[1]))
+	}),
+	newPhrase(`(?:on )?(`+enMonths+`) (\d{1,2})(?:st|nd|rd|th)?`, func(p *parser, groups []string) error {
+		return p.setMonthDay(groups[1], monthNumber(enMonths, groups[0]))
+	}),
+	newPhrase(`(\d{1,2}) (`+ruMonths+`)`, func(p *parser, groups []string) error {
+		return p.setMonthDay(groups[0], monthNumber(ruMonths, groups[1]))
+	}),
+	newPhrase(`(?:on |next |this )?(`+enWeekday+`)`, nextWeekday),
+	newPhrase(`(?:в|во) (`+ruWeekdays+`)`, nextWeekday),
+}
+
+// parser holds the state of a single parse
+type parser struct {
+	today   time.Time
+	date    time.Time
+	hasDate bool
+	repeat  string
+	// monthly is set for monthly rules without a day, taken from the date
+	monthly bool
+}
+
+// Parse parses free-form text into a task. The task date is always set,
+// today by default, and the task should be validated as any other task.
+func Parse(text string, now time.Time) (db.Task, error) {
+	p := parser{today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)}
+
+	var err error
+	if text, err = p.match(text, repeatPhrases); err != nil {
+		return db.Task{}, err
+	}
+	if text, err = p.match(text, datePhrases); err != nil {
+		return db.Task{}, err
+	}
+
+	title := strings.Trim(strings.Join(strings.Fields(text), " "), " ,.!?-")
+	if title == "" {
+		return db.Task{}, errors.New("task title is empty")
+	}
+
+	if err := p.resolve(); err != nil {
+		return db.Task{}, err
+	}
+	return db.Task{
+		Date:   p.date.Format(repeat.DateFormat),
+		Title:  title,
+		Repeat: p.repeat,
+	}, nil
+}
+
+// match applies the first matching phrase and cuts it out of the text
+func (p *parser) match(text string, phrases []phrase) (string, error) {
+	for _, ph := range phrases {
+		loc := ph.re.FindStringSubmatchIndex(text)
+		if loc == nil {
+			continue
+		}
+
+		var groups []string
+		for i := 2; i < len(loc); i += 2 {
+			if loc[i] >= 0 && loc[i+1] > loc[i] {
+				groups = append(groups, text[loc[i]:loc[i+1]])
+			}
+		}
+		if err := ph.apply(p, groups); err != nil {
+			return "", err
+		}
+		return text[:loc[0]] + " " + text[loc[1]:], nil
+	}
+	return text, nil
+}
+
+// resolve sets the default date and completes the repeat rule
+func (p *parser) resolve() error {
+	if p.monthly {
+		if !p.hasDate {
+			p.setDate(p.today)
+		}
+		p.repeat = "m " + strconv.Itoa(p.date.Day())
+	}
+
+	if p.hasDate {
+		return nil
+	}
+	p.date = p.today
+	if p.repeat == "" || (p.repeat[0] != 'w' && p.repeat[0] != 'm') {
+		return nil
+	}
+
+	// Rules on specific days start at the first matching day from today
+	rule, err := repeat.Parse(p.repeat)
+	if err != nil {
+		return err
+	}
+	first, err := rule.Next(p.today.AddDate(0, 0, -1), p.today)
+	if err != nil {
+		return err
+	}
+	p.date = first
+	return nil
+}
+
+func (p *parser) setDate(date time.Time) {
+	p.date = date
+	p.hasDate = true
+}
+
+// setMonthDay sets the nearest date with the given day and month from today
+func (p *parser) setMonthDay(dayStr string, month int) error {
+	day, err := strconv.Atoi(dayStr)
+	if err != nil || day < 1 || day > 31 {
+		return errors.New("invalid day " + dayStr)
+	}
+
+	for year := p.today.Year(); year <= p.today.Year()+4; year++ {
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		// Skip dates that do not exist in this year, like February 29
+		if date.Day() == day && !date.Before(p.today) {
+			p.setDate(date)
+			return nil
+		}
+	}
+	return errors.New("invalid day " + dayStr)
+}
+
+// monthDay sets a monthly rule on a day number or the last day of the month
+func monthDay(p *parser, groups []string) error {
+	if len(groups) == 0 {
+		return errors.New("invalid day of month")
+	}
+	if _, err := strconv.Atoi(groups[0]); err != nil {
+		p.repeat = "m -1"
+		return nil
+	}
+	p.repeat = "m " + groups[0]
+	return nil
+}
+
+// everyDays sets a daily rule with the interval in units of the given number of days
+func everyDays(unit int) func(p *parser, groups []string) error {
+	return func(p *parser, groups []string) error {
+		n, err := number(groups)
+		if err != nil {
+			return err
+		}
+		p.repeat = "d " + strconv.Itoa(n*unit)
+		return nil
+	}
+}
+
+// fixedRule sets the given repeat rule
+func fixedRule(rule string) func(p *parser, groups []string) error {
+	return func(p *parser, _ []string) error {
+		p.repeat = rule
+		return nil
+	}
+}
+
+// inDays sets the date the given number of days from today
+func inDays(days int) func(p *parser, groups []string) error {
+	return func(p *parser, _ []string) error {
+		p.setDate(p.today.AddDate(0, 0, days))
+		return nil
+	}
+}
+
+// weekdays sets a weekly rule on the listed weekdays
+func weekdays(p *parser, groups []string) error {
+	var flags [8]bool
+	for _, name := range weekdayName.FindAllString(groups[0], -1) {
+		flags[weekdayNumber(name)] = true
+	}
+
+	var days []string
+	for day := 1; day <= 7; day++ {
+		if flags[day] {
+			days = append(days, strconv.Itoa(day))
+		}
+	}
+	p.repeat = "w " + strings.Join(days, ",")
+	return nil
+}
+
+// nextWeekday sets the date to the nearest given weekday after today
+func nextWeekday(p *parser, groups []string) error {
+	weekday := weekdayNumber(groups[0]) % 7
+	days := (weekday - int(p.today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	p.setDate(p.today.AddDate(0, 0, days))
+	return nil
+}
+
+// weekdayNumber returns weekday number 1-7, where 1=Monday, 7=Sunday
+func weekdayNumber(name string) int {
+	name = strings.ToLower(name)
+	for i, stem := range ruWeekdayStems {
+		if strings.HasPrefix(name, stem) {
+			if i == 0 {
+				return 7
+			}
+			return i
+		}
+	}
+	for i, prefix := range []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"} {
+		if strings.HasPrefix(name, prefix) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// monthNumber returns the position of the month name in the list of names
+func monthNumber(names, name string) int {
+	name = strings.ToLower(name)
+	for i, month := range strings.Split(names, "|") {
+		if month == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// number returns the first group as a positive number
+func number(groups []string) (int, error) {
+	if len(groups) == 0 {
+		return 0, errors.New("invalid number")
+	}
+	n, err := strconv.Atoi(groups[0])
+	if err != nil || n <= 0 {
+		return 0, errors.New("invalid number " + groups[0])
+	}
+	return n, nil
+}
@@ -0,0 +1,109 @@
+package repeat
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// aliases are built-in names of common rules
+var aliases = map[string]string{
+	"daily":     "d 1",
+	"workdays":  "w 1,2,3,4,5",
+	"weekends":  "w 6,7",
+	"quarterly": "RRULE:FREQ=MONTHLY;INTERVAL=3",
+}
+
+// presetName is the format of user-defined rule names.
+// Built-in rule types are single letters, registered ones are checked by name.
+var presetName = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+// presets are user-defined named rules, they are looked up on every parse,
+// so an edited preset applies to all tasks referring to it
+var (
+	presetsMu sync.RWMutex
+	presets   = map[string]string{}
+)
+
+// Aliases returns built-in rule names with their rules
+func Aliases() map[string]string {
+	return maps.Clone(aliases)
+}
+
+// SetPresets replaces user-defined named rules.
+// Presets named as rule types registered after they were saved are ignored, the rule type wins.
+func SetPresets(named map[string]string) {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	presets = maps.Clone(named)
+	maps.DeleteFunc(presets, func(name, _ string) bool {
+		_, ok := lookupHandler(name)
+		return ok
+	})
+}
+
+// ValidatePreset checks a name and a rule of user-defined preset.
+// The rule may use built-in aliases but not other presets, neither whole nor combined.
+func ValidatePreset(name, repeat string) error {
+	if !presetName.MatchString(name) {
+		return errors.New("preset name must be 2-32 lowercase letters, digits, '-' or '_' starting with a letter")
+	}
+	if _, ok := aliases[name]; ok {
+		return errors.New("preset name is a built-in alias")
+	}
+	if _, ok := lookupHandler(name); ok {
+		return errors.New("preset name is a rule type")
+	}
+	for _, item := range splitUnion(repeat) {
+		token := strings.TrimSpace(item.Value)
+		if _, ok := lookupPreset(token); ok {
+			offset := utf8.RuneCountInString(repeat[:item.Offset+strings.Index(item.Value, token)])
+			return &RuleError{Code: CodeConflict, Message: "preset can't refer to another preset", Token: token, Offset: offset, part: -1}
+		}
+	}
+	_, err := Parse(repeat)
+	return err
+}
+
+// RefersTo checks if the rule refers to the preset, as the whole rule or one of combined rules
+func RefersTo(repeat, name string) bool {
+	for _, item := range splitUnion(repeat) {
+		if strings.TrimSpace(item.Value) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandPreset returns the rule with references to the preset replaced by the preset rule
+func ExpandPreset(repeat, name, rule string) string {
+	items := splitUnion(repeat)
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value := strings.TrimSpace(item.Value)
+		if value == name {
+			value = rule
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, unionSeparator+" ")
+}
+
+// lookupPreset returns the rule of a user-defined preset
+func lookupPreset(name string) (string, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	repeat, ok := presets[name]
+	return repeat, ok
+}
+
+// expandAlias replaces a built-in alias with its rule
+func expandAlias(repeat string) string {
+	if rule, ok := aliases[strings.TrimSpace(repeat)]; ok {
+		return rule
+	}
+	return repeat
+}
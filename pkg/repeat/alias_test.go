@@ -0,0 +1,31 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresetReferences(t *testing.T) {
+	SetPresets(map[string]string{"payday": "m 5; m 20"})
+	t.Cleanup(func() { SetPresets(nil) })
+
+	rule, err := Parse("w 1; payday")
+	assert.NoError(t, err)
+	next, err := rule.Next(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "20240108", next.Format(DateFormat))
+
+	assert.True(t, RefersTo("payday", "payday"))
+	assert.True(t, RefersTo("w 1;  payday ", "payday"))
+	assert.False(t, RefersTo("payday2; w 1", "payday"))
+	assert.Equal(t, "w 1; m 5", ExpandPreset("w 1;payday", "payday", "m 5"))
+	assert.Equal(t, "payday2", ExpandPreset("payday2", "payday", "m 5"))
+
+	var ruleErr *RuleError
+	if assert.ErrorAs(t, ValidatePreset("salary", "m 1; payday"), &ruleErr) {
+		assert.Equal(t, CodeConflict, ruleErr.Code)
+		assert.Equal(t, 5, ruleErr.Offset)
+	}
+}
@@ -0,0 +1,193 @@
+package repeat
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCronDays protects against infinite loop (February 29 on a given weekday repeats in 28 years)
+const maxCronDays = 366 * 30
+
+// cronMonthNames maps month names allowed in cron expressions to numbers
+var cronMonthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+// cronWeekdayNames maps weekday names allowed in cron expressions to numbers
+var cronWeekdayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// cronRule repeats on dates matching a cron expression: c [<minute> <hour>] <day> <month> <weekday>
+// Minute and hour fields are validated but ignored, since tasks have no time.
+type cronRule struct {
+	Fields []string
+	// Days indices 1-31, 0 is unused
+	Days [32]bool
+	// Months indices 1-12, 0 is unused
+	Months [13]bool
+	// Weekdays indices 0-6 (0=Sunday)
+	Weekdays [7]bool
+	// AnyDay and AnyWeekday are set when the field starts with '*'
+	AnyDay     bool
+	AnyWeekday bool
+}
+
+// cronHandler handles 'c' rules
+type cronHandler struct{}
+
+// Parse parses cron repeat rule: c [<minute> <hour>] <day> <month> <weekday>
+func (cronHandler) Parse(parts []string) (Rule, error) {
+	fields := parts[1:]
+	if len(fields) != 3 && len(fields) != 5 {
+		return nil, ruleError(CodeFormat, "cron expression must have 3 or 5 fields")
+	}
+
+	rule := cronRule{Fields: fields}
+	// part is the index of the day of month field in the rule parts
+	part := 1
+	if len(fields) == 5 {
+		if _, err := parseCronField(parts, 1, "minute", 0, 59, nil); err != nil {
+			return nil, err
+		}
+		if _, err := parseCronField(parts, 2, "hour", 0, 23, nil); err != nil {
+			return nil, err
+		}
+		fields = fields[2:]
+		part = 3
+	}
+
+	days, err := parseCronField(parts, part, "day of month", 1, 31, nil)
+	if err != nil {
+		return nil, err
+	}
+	copy(rule.Days[:], days)
+
+	months, err := parseCronField(parts, part+1, "month", 1, 12, cronMonthNames)
+	if err != nil {
+		return nil, err
+	}
+	copy(rule.Months[:], months)
+
+	// Both 0 and 7 are Sunday
+	weekdays, err := parseCronField(parts, part+2, "day of week", 0, 7, cronWeekdayNames)
+	if err != nil {
+		return nil, err
+	}
+	copy(rule.Weekdays[:], weekdays)
+	if weekdays[7] {
+		rule.Weekdays[0] = true
+	}
+
+	rule.AnyDay = strings.HasPrefix(fields[0], "*")
+	rule.AnyWeekday = strings.HasPrefix(fields[2], "*")
+	return rule, nil
+}
+
+// Describe describes the cron rule
+func (cronHandler) Describe(rule Rule, lang string) string {
+	return rule.(cronRule).describe(lang)
+}
+
+// parseCronField parses a cron field in the given rule part with lists, ranges and steps into flags min-max
+func parseCronField(parts []string, part int, name string, min, max int, names map[string]int) ([]bool, error) {
+	flags := make([]bool, max+1)
+	for _, item := range splitList(parts[part]) {
+		fail := func(message string) error {
+			return itemError(CodeValue, part, item, "invalid cron "+name+": "+message)
+		}
+		rangeStr, stepStr, hasStep := strings.Cut(item.Value, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return nil, fail("invalid step " + stepStr)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangeStr == "*":
+			start, end = min, max
+		case strings.Contains(rangeStr, "-"):
+			startStr, endStr, _ := strings.Cut(rangeStr, "-")
+			var err error
+			if start, err = parseCronValue(startStr, min, max, names); err != nil {
+				return nil, fail(err.Error())
+			}
+			if end, err = parseCronValue(endStr, min, max, names); err != nil {
+				return nil, fail(err.Error())
+			}
+			if start > end {
+				return nil, fail("invalid range " + rangeStr)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(rangeStr, min, max, names); err != nil {
+				return nil, fail(err.Error())
+			}
+			// A single value with step runs to the end of the field
+			end = start
+			if hasStep {
+				end = max
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			flags[value] = true
+		}
+	}
+	return flags, nil
+}
+
+// parseCronValue parses a single cron value given as a number or a name
+func parseCronValue(valueStr string, min, max int, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToUpper(valueStr)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value < min || value > max {
+		return 0, errors.New("invalid value " + valueStr)
+	}
+	return value, nil
+}
+
+// Next checks day by day from the later of the start date and now for a matching date
+func (r cronRule) Next(now, from time.Time) (time.Time, error) {
+	currentDate := from
+	if tomorrow := now.AddDate(0, 0, 1); tomorrow.After(currentDate) {
+		currentDate = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, from.Location())
+	}
+
+	for i := 0; i < maxCronDays; i++ {
+		if afterDay(currentDate, now) && r.matches(currentDate) {
+			return currentDate, nil
+		}
+		currentDate = currentDate.AddDate(0, 0, 1)
+	}
+	return time.Time{}, errors.New("cannot find next date for cron expression")
+}
+
+// matches checks the date against the cron fields.
+// As in cron, when both day of month and day of week are restricted, either one matches.
+func (r cronRule) matches(date time.Time) bool {
+	if !r.Months[date.Month()] {
+		return false
+	}
+
+	dayMatches := r.Days[date.Day()]
+	weekdayMatches := r.Weekdays[date.Weekday()]
+	if r.AnyDay || r.AnyWeekday {
+		return dayMatches && weekdayMatches
+	}
+	return dayMatches || weekdayMatches
+}
+
+func (r cronRule) String() string {
+	return "c " + strings.Join(r.Fields, " ")
+}
@@ -0,0 +1,50 @@
+package repeat
+
+import (
+	"strconv"
+	"time"
+)
+
+// dailyRule repeats every Interval days: d <number>
+type dailyRule struct {
+	Interval int
+}
+
+// dailyHandler handles 'd' rules
+type dailyHandler struct{}
+
+// Parse parses daily repeat rule: d <number>
+func (dailyHandler) Parse(parts []string) (Rule, error) {
+	if len(parts) != 2 {
+		return nil, ruleError(CodeFormat, "invalid daily rule format")
+	}
+
+	interval, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, partError(CodeValue, 1, "invalid day interval")
+	}
+
+	if interval <= 0 || interval > 400 {
+		return nil, partError(CodeValue, 1, "invalid day interval")
+	}
+
+	return dailyRule{Interval: interval}, nil
+}
+
+// Describe describes the daily rule
+func (dailyHandler) Describe(rule Rule, lang string) string {
+	return rule.(dailyRule).describe(lang)
+}
+
+// Next jumps straight to the first interval after 'now', at least one interval from the start date
+func (r dailyRule) Next(now, from time.Time) (time.Time, error) {
+	intervals := 1
+	if days := daysBetween(from, now); days >= 0 {
+		intervals = days/r.Interval + 1
+	}
+	return from.AddDate(0, 0, intervals*r.Interval), nil
+}
+
+func (r dailyRule) String() string {
+	return "d " + strconv.Itoa(r.Interval)
+}
@@ -0,0 +1,596 @@
+package repeat
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Languages of rule descriptions
+const (
+	LangRU = "ru"
+	LangEN = "en"
+)
+
+// describer is implemented by rules which can be described in words
+type describer interface {
+	describe(lang string) string
+}
+
+// Describe returns a human-readable description of the rule in the given language
+func Describe(rule Rule, lang string) (string, error) {
+	if lang != LangRU && lang != LangEN {
+		return "", errors.New("unsupported language")
+	}
+	d, ok := rule.(describer)
+	if !ok {
+		return "", errors.New("rule can't be described")
+	}
+	return d.describe(lang), nil
+}
+
+// Weekday and month names, indices 1-7 and 1-12, 0 is unused
+var (
+	enWeekdays = [8]string{"", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+	enMonths   = [13]string{"", "January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"}
+
+	// ruWeekdays - nominative case: "понедельник"
+	ruWeekdays = [8]string{"", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье"}
+	// ruWeekdaysAcc - accusative case: "в среду"
+	ruWeekdaysAcc = [8]string{"", "понедельник", "вторник", "среду", "четверг", "пятницу", "субботу", "воскресенье"}
+	// ruWeekdaysDat - dative plural: "по средам"
+	ruWeekdaysDat = [8]string{"", "понедельникам", "вторникам", "средам", "четвергам", "пятницам", "субботам", "воскресеньям"}
+	// ruWeekdayGenders - grammatical gender: m, f or n
+	ruWeekdayGenders = [8]byte{0, 'm', 'm', 'f', 'm', 'f', 'f', 'n'}
+	// ruMonthsGen - genitive case: "февраля"
+	ruMonthsGen = [13]string{"", "января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря"}
+	// ruMonthsPrep - prepositional case: "в феврале"
+	ruMonthsPrep = [13]string{"", "январе", "феврале", "марте", "апреле", "мае", "июне",
+		"июле", "августе", "сентябре", "октябре", "ноябре", "декабре"}
+)
+
+func (r dailyRule) describe(lang string) string {
+	if lang == LangRU {
+		return ruEvery(r.Interval, 'm', "день", "дня", "дней")
+	}
+	return enEvery(r.Interval, "day")
+}
+
+func (r yearlyRule) describe(lang string) string {
+	switch {
+	case r.Policy == leapClamp && lang == LangRU:
+		return "каждый год, 29 февраля в невисокосные годы переносится на 28 февраля"
+	case r.Policy == leapClamp:
+		return "every year, February 29 is moved to February 28 in non-leap years"
+	case r.Policy == leapRoll && lang == LangRU:
+		return "каждый год, 29 февраля в невисокосные годы переносится на 1 марта"
+	case r.Policy == leapRoll:
+		return "every year, February 29 is moved to March 1 in non-leap years"
+	case r.Policy == leapSkip && lang == LangRU:
+		return "каждый год, 29 февраля только в високосные годы"
+	case r.Policy == leapSkip:
+		return "every year, February 29 only in leap years"
+	case lang == LangRU:
+		return "каждый год"
+	default:
+		return "every year"
+	}
+}
+
+func (r weeklyRule) describe(lang string) string {
+	interval := max(1, r.Interval)
+	if lang == LangRU {
+		return ruEvery(interval, 'f', "неделю", "недели", "недель") + " по " +
+			joinList(pick(r.Weekdays[:], ruWeekdaysDat[:]), lang)
+	}
+	return enEvery(interval, "week") + " on " + joinList(pick(r.Weekdays[:], enWeekdays[:]), lang)
+}
+
+func (r monthlyRule) describe(lang string) string {
+	var items []string
+	for day := 1; day <= 31; day++ {
+		if r.Days[day] {
+			items = append(items, dayOrdinal(day, lang))
+		}
+	}
+	for _, day := range r.NegativeDays {
+		items = append(items, dayOrdinal(day, lang))
+	}
+
+	if r.Interval > 0 {
+		if lang == LangRU {
+			return ruEvery(r.Interval, 'm', "месяц", "месяца", "месяцев") + " в " + joinList(items, lang) + " день"
+		}
+		return enEvery(r.Interval, "month") + " on the " + joinList(items, lang) + " day"
+	}
+
+	if lang == LangRU {
+		return "в " + joinList(items, lang) + " день " + ruMonthsOf(r.Months[:], r.AllMonths)
+	}
+	return "on the " + joinList(items, lang) + " day of " + enMonthsOf(r.Months[:], r.AllMonths)
+}
+
+func (r ordinalRule) describe(lang string) string {
+	var items []string
+	for _, ordinal := range r.Ordinals {
+		for weekday := 1; weekday <= 7; weekday++ {
+			if r.Weekdays[weekday] {
+				items = append(items, weekdayOrdinal(ordinal, weekday, lang))
+			}
+		}
+	}
+
+	if lang == LangRU {
+		return "в " + joinList(items, lang) + " " + ruMonthsOf(r.Months[:], r.AllMonths)
+	}
+	return "on the " + joinList(items, lang) + " of " + enMonthsOf(r.Months[:], r.AllMonths)
+}
+
+func (r habitRule) describe(lang string) string {
+	if lang == LangRU {
+		period := "неделю"
+		if r.Period == periodMonth {
+			period = "месяц"
+		}
+		return fmt.Sprintf("%d %s в %s", r.Times, ruPlural(r.Times, "раз", "раза", "раз"), period)
+	}
+
+	period := "week"
+	if r.Period == periodMonth {
+		period = "month"
+	}
+	switch r.Times {
+	case 1:
+		return "once a " + period
+	case 2:
+		return "twice a " + period
+	default:
+		return fmt.Sprintf("%d times a %s", r.Times, period)
+	}
+}
+
+func (r easterRule) describe(lang string) string {
+	days := r.Offset
+	if days < 0 {
+		days = -days
+	}
+
+	if lang == LangRU {
+		tradition := "православной"
+		if r.Tradition == easterWestern {
+			tradition = "западной"
+		}
+		switch {
+		case r.Offset == 0 && r.Tradition == easterWestern:
+			return "каждый год в западную Пасху"
+		case r.Offset == 0:
+			return "каждый год в православную Пасху"
+		case r.Offset > 0:
+			return fmt.Sprintf("каждый год через %d %s после %s Пасхи", days, ruPlural(days, "день", "дня", "дней"), tradition)
+		default:
+			return fmt.Sprintf("каждый год за %d %s до %s Пасхи", days, ruPlural(days, "день", "дня", "дней"), tradition)
+		}
+	}
+
+	tradition := "Orthodox"
+	if r.Tradition == easterWestern {
+		tradition = "Western"
+	}
+	unit := "days"
+	if days == 1 {
+		unit = "day"
+	}
+	switch {
+	case r.Offset == 0:
+		return "every year on " + tradition + " Easter"
+	case r.Offset > 0:
+		return fmt.Sprintf("every year %d %s after %s Easter", days, unit, tradition)
+	default:
+		return fmt.Sprintf("every year %d %s before %s Easter", days, unit, tradition)
+	}
+}
+
+func (r cronRule) describe(lang string) string {
+	allDays := allSet(r.Days[1:])
+	allWeekdays := allSet(r.Weekdays[:])
+
+	// Cron weekdays start from Sunday
+	var weekdays [8]bool
+	copy(weekdays[1:], r.Weekdays[1:])
+	weekdays[7] = r.Weekdays[0]
+
+	var days []string
+	for day := 1; day <= 31; day++ {
+		if r.Days[day] {
+			days = append(days, dayOrdinal(day, lang))
+		}
+	}
+
+	var description string
+	if lang == LangRU {
+		onDays := ruDayNumbers(days) + " числа"
+		switch {
+		case allDays && allWeekdays:
+			description = "каждый день"
+		case allWeekdays:
+			description = onDays
+		case allDays:
+			description = "по " + joinList(pick(weekdays[:], ruWeekdaysDat[:]), lang)
+		case r.AnyDay || r.AnyWeekday:
+			description = onDays + ", если это " + joinWith(pick(weekdays[:], ruWeekdays[:]), " или ")
+		default:
+			description = onDays + " или по " + joinList(pick(weekdays[:], ruWeekdaysDat[:]), lang)
+		}
+		if !allSet(r.Months[1:]) {
+			description += " в " + joinList(pick(r.Months[:], ruMonthsPrep[:]), lang)
+		}
+		return description
+	}
+
+	onDays := "on the " + joinList(days, lang)
+	switch {
+	case allDays && allWeekdays:
+		description = "every day"
+	case allWeekdays:
+		description = onDays
+	case allDays:
+		description = "on " + joinList(pick(weekdays[:], enWeekdays[:]), lang)
+	case r.AnyDay || r.AnyWeekday:
+		description = onDays + " if it is " + joinWith(pick(weekdays[:], enWeekdays[:]), " or ")
+	default:
+		description = onDays + " or on " + joinList(pick(weekdays[:], enWeekdays[:]), lang)
+	}
+	if !allSet(r.Months[1:]) {
+		description += " in " + joinList(pick(r.Months[:], enMonths[:]), lang)
+	}
+	return description
+}
+
+func (r rrule) describe(lang string) string {
+	var parts []string
+
+	switch r.Freq {
+	case freqDaily:
+		if lang == LangRU {
+			parts = append(parts, ruEvery(r.Interval, 'm', "день", "дня", "дней"))
+		} else {
+			parts = append(parts, enEvery(r.Interval, "day"))
+		}
+	case freqWeekly:
+		if lang == LangRU {
+			parts = append(parts, ruEvery(r.Interval, 'f', "неделю", "недели", "недель"))
+		} else {
+			parts = append(parts, enEvery(r.Interval, "week"))
+		}
+	case freqMonthly:
+		if lang == LangRU {
+			parts = append(parts, ruEvery(r.Interval, 'm', "месяц", "месяца", "месяцев"))
+		} else {
+			parts = append(parts, enEvery(r.Interval, "month"))
+		}
+	default:
+		if lang == LangRU {
+			parts = append(parts, ruEvery(r.Interval, 'm', "год", "года", "лет"))
+		} else {
+			parts = append(parts, enEvery(r.Interval, "year"))
+		}
+	}
+
+	if len(r.ByDay) > 0 {
+		parts = append(parts, r.describeByDay(lang))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		items := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			items = append(items, dayOrdinal(day, lang))
+		}
+		if lang == LangRU {
+			parts = append(parts, "в "+joinList(items, lang)+" день")
+		} else {
+			parts = append(parts, "on the "+joinList(items, lang)+" day")
+		}
+	}
+
+	if r.HasByMonth {
+		if lang == LangRU {
+			parts = append(parts, "в "+joinList(pick(r.ByMonth[:], ruMonthsPrep[:]), lang))
+		} else {
+			parts = append(parts, "in "+joinList(pick(r.ByMonth[:], enMonths[:]), lang))
+		}
+	}
+
+	return strings.Join(parts, " ") + describeEnd(r.Until, r.Count, lang)
+}
+
+// describeByDay describes RRULE weekdays with optional ordinals
+func (r rrule) describeByDay(lang string) string {
+	hasOrdinals := false
+	for _, day := range r.ByDay {
+		if day.Ordinal != 0 && r.Freq != freqDaily && r.Freq != freqWeekly {
+			hasOrdinals = true
+		}
+	}
+
+	items := make([]string, 0, len(r.ByDay))
+	for _, day := range r.ByDay {
+		switch {
+		case !hasOrdinals && lang == LangRU:
+			items = append(items, ruWeekdaysDat[day.Weekday])
+		case !hasOrdinals:
+			items = append(items, enWeekdays[day.Weekday])
+		case day.Ordinal == 0 && lang == LangRU:
+			items = append(items, ruGendered(ruWeekdayGenders[day.Weekday], "каждый", "каждую", "каждое")+" "+ruWeekdaysAcc[day.Weekday])
+		case day.Ordinal == 0:
+			items = append(items, "every "+enWeekdays[day.Weekday])
+		default:
+			items = append(items, weekdayOrdinal(day.Ordinal, day.Weekday, lang))
+		}
+	}
+
+	switch {
+	case !hasOrdinals && lang == LangRU:
+		return "по " + joinList(items, lang)
+	case !hasOrdinals:
+		return "on " + joinList(items, lang)
+	case lang == LangRU:
+		return "в " + joinList(items, lang)
+	default:
+		return "on the " + joinList(items, lang)
+	}
+}
+
+func (r unionRule) describe(lang string) string {
+	items := make([]string, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		items = append(items, describeRule(rule, lang))
+	}
+	return strings.Join(items, "; ")
+}
+
+func (r limitedRule) describe(lang string) string {
+	return describeRule(r.Rule, lang) + describeEnd(r.Until, r.Count, lang)
+}
+
+func (r exceptionRule) describe(lang string) string {
+	return describeRule(r.Rule, lang)
+}
+
+func (r businessRule) describe(lang string) string {
+	if lang == LangRU {
+		return ruEvery(r.Interval, 'm', "рабочий день", "рабочих дня", "рабочих дней")
+	}
+	return enEvery(r.Interval, "working day")
+}
+
+func (r workdayRule) describe(lang string) string {
+	description := describeRule(r.Rule, lang)
+	switch {
+	case r.Shift == shiftSkip && lang == LangRU:
+		return description + ", кроме выходных и праздников"
+	case r.Shift == shiftSkip:
+		return description + ", except days off"
+	case r.Shift == shiftNearest && lang == LangRU:
+		return description + ", с переносом на ближайший рабочий день того же месяца"
+	case r.Shift == shiftNearest:
+		return description + ", moved to the nearest working day of the same month if it falls on a day off"
+	case r.Shift == shiftPrev && lang == LangRU:
+		return description + ", с переносом на предыдущий рабочий день"
+	case r.Shift == shiftPrev:
+		return description + ", moved to the previous working day if it falls on a day off"
+	case lang == LangRU:
+		return description + ", с переносом на следующий рабочий день"
+	default:
+		return description + ", moved to the next working day if it falls on a day off"
+	}
+}
+
+// describeRule describes a wrapped rule, falling back to its text form
+func describeRule(rule Rule, lang string) string {
+	if d, ok := rule.(describer); ok {
+		return d.describe(lang)
+	}
+	return rule.String()
+}
+
+// describeEnd describes end conditions of a rule
+func describeEnd(until time.Time, count int, lang string) string {
+	var description string
+	if !until.IsZero() {
+		if lang == LangRU {
+			description += ", до " + until.Format("02.01.2006")
+		} else {
+			description += ", until " + until.Format("2006-01-02")
+		}
+	}
+	if count > 0 {
+		if lang == LangRU {
+			description += ", " + strconv.Itoa(count) + " " + ruPlural(count, "раз", "раза", "раз")
+		} else if count == 1 {
+			description += ", once"
+		} else {
+			description += ", " + strconv.Itoa(count) + " times"
+		}
+	}
+	return description
+}
+
+// enEvery describes an interval in English: "every day", "every 3 days"
+func enEvery(interval int, unit string) string {
+	if interval == 1 {
+		return "every " + unit
+	}
+	return fmt.Sprintf("every %d %ss", interval, unit)
+}
+
+// ruEvery describes an interval in Russian: "каждый день", "каждые 3 дня"
+func ruEvery(interval int, gender byte, one, few, many string) string {
+	every := ruGendered(gender, "каждый", "каждую", "каждое")
+	if interval == 1 {
+		return every + " " + one
+	}
+	if interval%10 != 1 || interval%100 == 11 {
+		every = "каждые"
+	}
+	return fmt.Sprintf("%s %d %s", every, interval, ruPlural(interval, one, few, many))
+}
+
+// ruPlural chooses Russian plural form for the number
+func ruPlural(n int, one, few, many string) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return one
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return few
+	default:
+		return many
+	}
+}
+
+// ruGendered chooses the word form for Russian grammatical gender
+func ruGendered(gender byte, masculine, feminine, neuter string) string {
+	switch gender {
+	case 'f':
+		return feminine
+	case 'n':
+		return neuter
+	default:
+		return masculine
+	}
+}
+
+// dayOrdinal describes a day of the month, negative days are counted from the end
+func dayOrdinal(day int, lang string) string {
+	if lang == LangRU {
+		switch {
+		case day == -1:
+			return "последний"
+		case day == -2:
+			return "предпоследний"
+		case day < 0:
+			return strconv.Itoa(-day) + "-й с конца"
+		default:
+			return strconv.Itoa(day) + "-й"
+		}
+	}
+
+	switch {
+	case day == -1:
+		return "last"
+	case day == -2:
+		return "second to last"
+	case day < 0:
+		return enOrdinal(-day) + " to last"
+	default:
+		return enOrdinal(day)
+	}
+}
+
+// ruDayNumbers lists days of the month in Russian genitive: "1-го и 15-го"
+func ruDayNumbers(days []string) string {
+	items := make([]string, 0, len(days))
+	for _, day := range days {
+		items = append(items, strings.TrimSuffix(day, "-й")+"-го")
+	}
+	return joinList(items, LangRU)
+}
+
+// weekdayOrdinal describes the Nth weekday of the month: "second Tuesday", "последнюю пятницу"
+func weekdayOrdinal(ordinal, weekday int, lang string) string {
+	if lang == LangRU {
+		gender := ruWeekdayGenders[weekday]
+		var prefix string
+		switch {
+		case ordinal == -1:
+			prefix = ruGendered(gender, "последний", "последнюю", "последнее")
+		case ordinal < 0:
+			prefix = strconv.Itoa(-ordinal) + ruGendered(gender, "-й", "-ю", "-е") + " с конца"
+		default:
+			prefix = strconv.Itoa(ordinal) + ruGendered(gender, "-й", "-ю", "-е")
+		}
+		return prefix + " " + ruWeekdaysAcc[weekday]
+	}
+
+	words := [6]string{"", "first", "second", "third", "fourth", "fifth"}
+	switch {
+	case ordinal == -1:
+		return "last " + enWeekdays[weekday]
+	case ordinal < 0:
+		return words[-ordinal] + " to last " + enWeekdays[weekday]
+	default:
+		return words[ordinal] + " " + enWeekdays[weekday]
+	}
+}
+
+// enOrdinal formats English ordinal number: 1st, 2nd, 3rd, 11th
+func enOrdinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// enMonthsOf describes months in English: "every month", "February and August"
+func enMonthsOf(months []bool, all bool) string {
+	if all {
+		return "every month"
+	}
+	return joinList(pick(months, enMonths[:]), LangEN)
+}
+
+// ruMonthsOf describes months in Russian: "каждого месяца", "февраля и августа"
+func ruMonthsOf(months []bool, all bool) string {
+	if all {
+		return "каждого месяца"
+	}
+	return joinList(pick(months, ruMonthsGen[:]), LangRU)
+}
+
+// pick returns names of set flags
+func pick(flags []bool, names []string) []string {
+	var items []string
+	for i, set := range flags {
+		if set && i < len(names) {
+			items = append(items, names[i])
+		}
+	}
+	return items
+}
+
+// allSet checks if all flags are set
+func allSet(flags []bool) bool {
+	for _, set := range flags {
+		if !set {
+			return false
+		}
+	}
+	return true
+}
+
+// joinList joins items as a list: "a, b and c" or "a, b и c"
+func joinList(items []string, lang string) string {
+	if lang == LangRU {
+		return joinWith(items, " и ")
+	}
+	return joinWith(items, " and ")
+}
+
+// joinWith joins items with commas and the last conjunction
+func joinWith(items []string, last string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + last + items[len(items)-1]
+}
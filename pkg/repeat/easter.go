@@ -0,0 +1,105 @@
+package repeat
+
+import (
+	"strconv"
+	"time"
+)
+
+// Easter computus traditions
+const (
+	easterOrthodox = "orthodox"
+	easterWestern  = "western"
+)
+
+// maxEasterOffset limits the offset from Easter to a year
+const maxEasterOffset = 365
+
+// easterRule repeats every year on the day shifted from Easter: e <offset> [orthodox|western]
+// Orthodox Easter is used by default, e.g. "e -48" is the Monday of Maslenitsa and "e 49" is Trinity.
+type easterRule struct {
+	Offset    int
+	Tradition string
+}
+
+// easterHandler handles 'e' rules
+type easterHandler struct{}
+
+// Parse parses Easter-relative repeat rule: e <offset> [orthodox|western]
+func (easterHandler) Parse(parts []string) (Rule, error) {
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, ruleError(CodeFormat, "invalid easter rule format")
+	}
+
+	offset, err := strconv.Atoi(parts[1])
+	if err != nil || offset < -maxEasterOffset || offset > maxEasterOffset {
+		return nil, partError(CodeValue, 1, "easter offset must be between -365 and 365")
+	}
+
+	rule := easterRule{Offset: offset, Tradition: easterOrthodox}
+	if len(parts) == 3 {
+		if parts[2] != easterOrthodox && parts[2] != easterWestern {
+			return nil, partError(CodeValue, 2, "easter tradition must be orthodox or western")
+		}
+		rule.Tradition = parts[2]
+	}
+	return rule, nil
+}
+
+// Describe describes the Easter rule
+func (easterHandler) Describe(rule Rule, lang string) string {
+	return rule.(easterRule).describe(lang)
+}
+
+// Next finds the first shifted Easter date from the start date after now.
+// The offset may move the date to the previous or the next year.
+func (r easterRule) Next(now, from time.Time) (time.Time, error) {
+	start := firstCandidate(now, from)
+	for year := start.Year() - 1; ; year++ {
+		month, day := r.easter(year)
+		date := time.Date(year, month, day+r.Offset,
+			from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), from.Location())
+		if !date.Before(start) {
+			return date, nil
+		}
+	}
+}
+
+// easter returns the Gregorian calendar date of Easter in the year
+func (r easterRule) easter(year int) (time.Month, int) {
+	if r.Tradition == easterWestern {
+		return westernEaster(year)
+	}
+	return orthodoxEaster(year)
+}
+
+// westernEaster computes Easter with the Gregorian computus (anonymous Gregorian algorithm)
+func westernEaster(year int) (time.Month, int) {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	n := h + l - 7*m + 114
+	return time.Month(n / 31), n%31 + 1
+}
+
+// orthodoxEaster computes Easter with the Julian computus (Meeus algorithm)
+// and converts it to the Gregorian calendar
+func orthodoxEaster(year int) (time.Month, int) {
+	a, b, c := year%4, year%7, year%19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	n := d + e + 114
+
+	// The Julian calendar is behind by 13 days in 1900-2099
+	julianLag := year/100 - year/400 - 2
+	return time.Month(n / 31), n%31 + 1 + julianLag
+}
+
+func (r easterRule) String() string {
+	return "e " + strconv.Itoa(r.Offset) + " " + r.Tradition
+}
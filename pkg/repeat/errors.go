@@ -0,0 +1,136 @@
+package repeat
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Codes of repeat rule errors
+const (
+	// CodeEmpty - the rule is empty
+	CodeEmpty = "empty"
+	// CodeUnknownType - the rule type is not supported
+	CodeUnknownType = "unknown_type"
+	// CodeFormat - the rule has a wrong number of parts
+	CodeFormat = "format"
+	// CodeValue - a value is invalid or out of range
+	CodeValue = "value"
+	// CodeDuplicate - a modifier or an RRULE part is repeated
+	CodeDuplicate = "duplicate"
+	// CodeConflict - options can't be used together
+	CodeConflict = "conflict"
+	// CodeNoOccurrences - the rule has no occurrences after the start date
+	CodeNoOccurrences = "no_occurrences"
+)
+
+// RuleError is a repeat rule error pointing to the offending token of the rule
+type RuleError struct {
+	Code    string
+	Message string
+	// Token is the offending part of the rule, the whole rule for errors in the rule as a whole
+	Token string
+	// Offset is the character offset of the token in the rule
+	Offset int
+
+	// part is the index of the offending rule part while parsing, -1 if Offset is final
+	part int
+}
+
+func (e *RuleError) Error() string {
+	return e.Message
+}
+
+// ruleError returns an error in the rule as a whole
+func ruleError(code, message string) *RuleError {
+	return &RuleError{Code: code, Message: message, part: -1}
+}
+
+// partError returns an error in a part of the rule
+func partError(code string, part int, message string) *RuleError {
+	return &RuleError{Code: code, Message: message, part: part}
+}
+
+// itemError returns an error in an item of a comma separated part of the rule
+func itemError(code string, part int, item listItem, message string) *RuleError {
+	return &RuleError{Code: code, Message: message, Token: item.Value, Offset: item.Offset, part: part}
+}
+
+// tokenError returns an error in a token at the byte offset in the rule
+func tokenError(code, token string, offset int, message string) *RuleError {
+	return &RuleError{Code: code, Message: message, Token: token, Offset: offset, part: -1}
+}
+
+// locate fills the token of the error and turns its byte offset into a character offset
+func locate(err error, repeat string, parts []token) error {
+	var ruleErr *RuleError
+	if !errors.As(err, &ruleErr) {
+		return err
+	}
+
+	if ruleErr.part >= 0 && ruleErr.part < len(parts) {
+		part := parts[ruleErr.part]
+		if ruleErr.Token == "" {
+			ruleErr.Token = part.Value
+		}
+		ruleErr.Offset += part.Offset
+	} else if ruleErr.Token == "" {
+		ruleErr.Token = strings.TrimSpace(repeat)
+		ruleErr.Offset = strings.Index(repeat, ruleErr.Token)
+	}
+	ruleErr.part = -1
+
+	if ruleErr.Offset > len(repeat) {
+		ruleErr.Offset = len(repeat)
+	}
+	ruleErr.Offset = utf8.RuneCountInString(repeat[:ruleErr.Offset])
+	return ruleErr
+}
+
+// token is a space separated part of the rule with its byte offset
+type token struct {
+	Value  string
+	Offset int
+}
+
+// tokenize splits the rule by spaces like strings.Fields, keeping offsets
+func tokenize(repeat string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range repeat {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			tokens = append(tokens, token{Value: repeat[start:i], Offset: start})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{Value: repeat[start:], Offset: start})
+	}
+	return tokens
+}
+
+// listItem is an item of a comma separated list with its byte offset in the list
+type listItem struct {
+	Value  string
+	Offset int
+}
+
+// splitList splits a comma separated list, keeping offsets
+func splitList(list string) []listItem {
+	return splitBy(list, ",")
+}
+
+// splitBy splits a list by the separator, keeping offsets
+func splitBy(list, sep string) []listItem {
+	var items []listItem
+	offset := 0
+	for _, value := range strings.Split(list, sep) {
+		items = append(items, listItem{Value: value, Offset: offset})
+		offset += len(value) + len(sep)
+	}
+	return items
+}
@@ -0,0 +1,95 @@
+package repeat
+
+import (
+	"errors"
+	"time"
+)
+
+// Exceptions maps occurrence dates of a rule to their moved dates.
+// An empty moved date skips the occurrence.
+type Exceptions map[string]string
+
+// exceptionRule skips and moves single occurrences of a rule
+type exceptionRule struct {
+	Rule
+	Exceptions Exceptions
+}
+
+// WithExceptions returns the rule with the given occurrences skipped or moved
+func WithExceptions(rule Rule, exceptions Exceptions) Rule {
+	if len(exceptions) == 0 {
+		return rule
+	}
+	return exceptionRule{Rule: rule, Exceptions: exceptions}
+}
+
+// Next returns the earliest of the next regular occurrence and moved occurrences after now
+func (r exceptionRule) Next(now, from time.Time) (time.Time, error) {
+	var next time.Time
+	var nextErr error
+
+	// Find the next occurrence which is not changed
+	current := now
+	for i := 0; i <= len(r.Exceptions); i++ {
+		occurrence, err := r.Rule.Next(current, from)
+		if err != nil {
+			nextErr = err
+			break
+		}
+		if _, ok := r.Exceptions[occurrence.Format(DateFormat)]; !ok {
+			next = occurrence
+			break
+		}
+		current = occurrence
+	}
+
+	// Moved occurrences of the series may come earlier,
+	// except the start date and the one it is already moved to
+	for dateStr, movedStr := range r.Exceptions {
+		if movedStr == "" || movedStr == from.Format(DateFormat) {
+			continue
+		}
+		date, err := time.ParseInLocation(DateFormat, dateStr, from.Location())
+		if err != nil || !afterDay(date, from) {
+			continue
+		}
+		moved, err := time.ParseInLocation(DateFormat, movedStr, from.Location())
+		if err != nil || !afterDay(moved, now) {
+			continue
+		}
+		if next.IsZero() || moved.Before(next) {
+			next = moved
+		}
+	}
+
+	if next.IsZero() {
+		if nextErr == nil {
+			nextErr = errors.New("cannot find next date")
+		}
+		return time.Time{}, nextErr
+	}
+	return next, nil
+}
+
+// SeriesDate returns the regular occurrence the date stands for: the original date
+// if the occurrence is moved to the date, or the date itself. Next occurrences
+// are counted from it, so moving an occurrence doesn't shift the rest of the series.
+func SeriesDate(rule Rule, date time.Time) time.Time {
+	r, ok := rule.(exceptionRule)
+	if !ok {
+		return date
+	}
+
+	series := date
+	for dateStr, movedStr := range r.Exceptions {
+		if movedStr != date.Format(DateFormat) {
+			continue
+		}
+		// Several occurrences may be moved to the same date, the latest one is the current
+		original, err := time.ParseInLocation(DateFormat, dateStr, date.Location())
+		if err == nil && (series.Equal(date) || original.After(series)) {
+			series = original
+		}
+	}
+	return series
+}
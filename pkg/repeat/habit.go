@@ -0,0 +1,101 @@
+package repeat
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Habit quota periods
+const (
+	periodWeek  = "w"
+	periodMonth = "m"
+)
+
+// habitRule keeps a task active every day until it is done the given number of times
+// in the current week or month: h <times> w|m
+// Completions are counted by the caller, see PeriodQuota.
+type habitRule struct {
+	Times  int
+	Period string
+}
+
+// Quota is the completion quota of a habit rule in the period containing a date
+type Quota struct {
+	Times int
+	// Start is the first day of the period
+	Start time.Time
+	// End is the first day of the next period
+	End time.Time
+}
+
+// habitHandler handles 'h' rules
+type habitHandler struct{}
+
+// Parse parses habit repeat rule: h <times> w|m
+func (habitHandler) Parse(parts []string) (Rule, error) {
+	if len(parts) != 3 {
+		return nil, ruleError(CodeFormat, "invalid habit rule format")
+	}
+
+	maxTimes := 7
+	switch parts[2] {
+	case periodWeek:
+	case periodMonth:
+		maxTimes = 31
+	default:
+		return nil, partError(CodeValue, 2, "habit period must be w or m")
+	}
+
+	times, err := strconv.Atoi(parts[1])
+	if err != nil || times < 1 || times > maxTimes {
+		return nil, partError(CodeValue, 1, fmt.Sprintf("habit quota must be between 1 and %d", maxTimes))
+	}
+	return habitRule{Times: times, Period: parts[2]}, nil
+}
+
+// Describe describes the habit rule
+func (habitHandler) Describe(rule Rule, lang string) string {
+	return rule.(habitRule).describe(lang)
+}
+
+// Next returns the next day the task is active, assuming the quota is not met yet
+func (r habitRule) Next(now, from time.Time) (time.Time, error) {
+	return firstCandidate(now, from), nil
+}
+
+// period returns the first days of the period containing the date and of the next period
+func (r habitRule) period(date time.Time) (time.Time, time.Time) {
+	year, month, day := date.Date()
+	if r.Period == periodMonth {
+		start := time.Date(year, month, 1, 0, 0, 0, 0, date.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+	start := time.Date(year, month, day-isoWeekday(date)+1, 0, 0, 0, 0, date.Location())
+	return start, start.AddDate(0, 0, 7)
+}
+
+func (r habitRule) String() string {
+	return "h " + strconv.Itoa(r.Times) + " " + r.Period
+}
+
+// PeriodQuota returns the quota of the period containing the date, or false if the rule is not a habit rule
+func PeriodQuota(rule Rule, date time.Time) (Quota, bool) {
+	switch r := rule.(type) {
+	case habitRule:
+		start, end := r.period(date)
+		return Quota{Times: r.Times, Start: start, End: end}, true
+	case exceptionRule:
+		return PeriodQuota(r.Rule, date)
+	case handledRule:
+		return PeriodQuota(r.Rule, date)
+	default:
+		return Quota{}, false
+	}
+}
+
+// isHabit checks if the rule is a habit rule
+func isHabit(rule Rule) bool {
+	_, ok := PeriodQuota(rule, time.Time{})
+	return ok
+}
@@ -0,0 +1,112 @@
+package repeat
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RuleHandler implements a rule type selected by the first part of a rule.
+// Rule types other than the built-in ones may be added with Register.
+//
+// The handler has no Next of its own: the Rule returned by Parse is the next date logic
+// of the type, built-in rules included. A type hooks into scheduling by implementing Rule.Next,
+// which is then called with the same now and start date as a handler method would be,
+// and modifiers, exceptions, counts and combined rules wrap it like any built-in rule.
+// A second Next on the handler would only have to repeat it.
+type RuleHandler interface {
+	// Parse parses the rule parts, parts[0] is the rule type.
+	// Invalid parts should be reported with PartError.
+	Parse(parts []string) (Rule, error)
+	// Describe describes the parsed rule in words in the language, LangRU or LangEN
+	Describe(rule Rule, lang string) string
+}
+
+// handlers are rule handlers by rule type
+var (
+	handlersMu sync.RWMutex
+	handlers   = map[string]RuleHandler{}
+)
+
+func init() {
+	// d <number> - repeat every <number> days
+	Register("d", dailyHandler{})
+	// y [clamp|roll|skip] - repeat yearly with February 29 policy
+	Register("y", yearlyHandler{})
+	// w <weekdays> [/<interval>] - repeat weekly on specified weekdays
+	Register("w", weeklyHandler{})
+	// m <monthdays> [<months>|/<interval>] - repeat monthly on specified days
+	Register("m", monthlyHandler{})
+	// n <ordinals> <weekdays> [<months>] - repeat on the Nth weekdays of the month
+	Register("n", ordinalHandler{})
+	// c [<minute> <hour>] <day> <month> <weekday> - repeat on cron schedule
+	Register("c", cronHandler{})
+	// b <number> - repeat every <number> working days
+	Register("b", businessHandler{})
+	// e <offset> [orthodox|western] - repeat yearly relative to Easter
+	Register("e", easterHandler{})
+	// h <times> w|m - repeat until done <times> in a week or a month
+	Register("h", habitHandler{})
+}
+
+// Register adds the handler of the rule type, replacing the existing one.
+// It panics on a rule type which can't be told from other rules,
+// built-in aliases and user-defined presets included.
+func Register(ruleType string, handler RuleHandler) {
+	if ruleType == "" || strings.ContainsAny(ruleType, " \t;") || isRRule(ruleType) {
+		panic("repeat: invalid rule type " + ruleType)
+	}
+	if _, ok := aliases[ruleType]; ok {
+		panic("repeat: rule type " + ruleType + " is a built-in alias")
+	}
+	if _, ok := lookupPreset(ruleType); ok {
+		panic("repeat: rule type " + ruleType + " is a preset")
+	}
+
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers[ruleType] = handler
+}
+
+// RuleTypes returns registered rule types in sorted order
+func RuleTypes() []string {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	types := make([]string, 0, len(handlers))
+	for ruleType := range handlers {
+		types = append(types, ruleType)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// lookupHandler returns the handler of the rule type
+func lookupHandler(ruleType string) (RuleHandler, bool) {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	handler, ok := handlers[ruleType]
+	return handler, ok
+}
+
+// handledRule is a rule parsed by a registered handler, which describes it
+type handledRule struct {
+	Rule
+	handler RuleHandler
+}
+
+func (r handledRule) describe(lang string) string {
+	return r.handler.Describe(r.Rule, lang)
+}
+
+// unwrapHandled returns the rule parsed by a handler
+func unwrapHandled(rule Rule) Rule {
+	if r, ok := rule.(handledRule); ok {
+		return r.Rule
+	}
+	return rule
+}
+
+// PartError returns an error in the rule part with the index, for use in RuleHandler.Parse
+func PartError(code string, part int, message string) error {
+	return partError(code, part, message)
+}
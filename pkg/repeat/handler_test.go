@@ -0,0 +1,58 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fortnightHandler handles 'fortnight' rules, every 14 days
+type fortnightHandler struct{}
+
+func (fortnightHandler) Parse(parts []string) (Rule, error) {
+	if len(parts) != 1 {
+		return nil, PartError(CodeFormat, 1, "fortnight takes no values")
+	}
+	return dailyRule{Interval: 14}, nil
+}
+
+func (fortnightHandler) Describe(rule Rule, lang string) string {
+	return "fortnightly"
+}
+
+func TestRegister(t *testing.T) {
+	Register("fortnight", fortnightHandler{})
+	t.Cleanup(func() {
+		handlersMu.Lock()
+		defer handlersMu.Unlock()
+		delete(handlers, "fortnight")
+	})
+
+	rule, err := Parse("fortnight")
+	assert.NoError(t, err)
+	next, err := rule.Next(time.Date(2024, 1, 26, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "20240129", next.Format(DateFormat))
+	description, err := Describe(rule, LangEN)
+	assert.NoError(t, err)
+	assert.Equal(t, "fortnightly", description)
+
+	// A rule type can't be used as a preset name, and a stale preset of the name is ignored
+	assert.Error(t, ValidatePreset("fortnight", "d 1"))
+	SetPresets(map[string]string{"fortnight": "d 1"})
+	t.Cleanup(func() { SetPresets(nil) })
+	rule, err = Parse("fortnight")
+	assert.NoError(t, err)
+	assert.Equal(t, "d 14", rule.String())
+}
+
+func TestRegisterConflict(t *testing.T) {
+	SetPresets(map[string]string{"standup": "w 1,2,3,4,5"})
+	t.Cleanup(func() { SetPresets(nil) })
+
+	for _, ruleType := range []string{"", "d 1", "RRULE:FREQ=DAILY", "daily", "standup"} {
+		assert.Panics(t, func() { Register(ruleType, fortnightHandler{}) }, ruleType)
+	}
+	assert.NotContains(t, RuleTypes(), "standup")
+}
@@ -0,0 +1,178 @@
+package repeat
+
+import (
+	"errors"
+	"time"
+)
+
+// Iterator walks over successive occurrences of a rule
+type Iterator struct {
+	rule Rule
+	now  time.Time
+	from time.Time
+	// left is the number of occurrences left for count limited rules, -1 if unlimited
+	left int
+	// start is set until the first occurrence when the start date after now is yet to be counted
+	start bool
+	// err is returned instead of occurrences when they can't be counted
+	err error
+}
+
+// NewIterator creates an iterator over occurrences of the rule after now.
+// Occurrences of count limited rules are counted from the start date, which is the first of them.
+func NewIterator(rule Rule, now, from time.Time) *Iterator {
+	// The iterator counts occurrences itself, so the rule doesn't recount them on every step
+	it := &Iterator{rule: withoutCount(rule), now: now, from: from, left: -1}
+	if count := OccurrenceCount(rule); count > 0 {
+		counted, err := countedUntil(it.rule, now, from, count)
+		it.left, it.err = count-counted, err
+		it.start = afterDay(from, now)
+	}
+	return it
+}
+
+// Next returns the next occurrence of the rule
+func (it *Iterator) Next() (time.Time, error) {
+	if it.err != nil {
+		return time.Time{}, it.err
+	}
+	if it.left == 0 {
+		return time.Time{}, ErrNoMoreOccurrences
+	}
+
+	next, err := it.rule.Next(it.now, it.from)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// The start date is counted when the rule doesn't return it, e.g. every 7 days after it
+	if it.start {
+		it.start = false
+		if afterDay(next, it.from) {
+			if it.left--; it.left == 0 {
+				return time.Time{}, ErrNoMoreOccurrences
+			}
+		}
+	}
+
+	// Every next occurrence is searched after the previous one
+	it.now = next
+	if it.left > 0 {
+		it.left--
+	}
+	return next, nil
+}
+
+// Left returns the number of occurrences left after the last returned one,
+// -1 if the rule is not limited by count
+func (it *Iterator) Left() int {
+	return it.left
+}
+
+// Take returns up to count next occurrences not later than until.
+// Zero until means no upper bound.
+func (it *Iterator) Take(count int, until time.Time) ([]time.Time, error) {
+	dates := make([]time.Time, 0, count)
+	for len(dates) < count {
+		next, err := it.Next()
+		if errors.Is(err, ErrNoMoreOccurrences) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !until.IsZero() && afterDay(next, until) {
+			break
+		}
+		dates = append(dates, next)
+	}
+	return dates, nil
+}
+
+// maxOccurrenceWalk limits occurrences walked through by Prev and Between.
+// Rules without count are walked from shortly before the dates, count limited rules from the start date.
+const maxOccurrenceWalk = 10000
+
+// errTooManyOccurrences is returned when the walk exceeds maxOccurrenceWalk
+var errTooManyOccurrences = errors.New("too many occurrences before the date")
+
+// Prev returns the last occurrence of the rule on or before now.
+// As for count limited rules, the start date is the first occurrence.
+func Prev(rule Rule, now, from time.Time) (time.Time, error) {
+	if afterDay(from, now) {
+		return time.Time{}, errors.New("no previous occurrence")
+	}
+
+	// Occurrences of rules without count don't depend on the earlier ones,
+	// so the search starts a week before now and goes further back while there are none
+	if OccurrenceCount(rule) == 0 {
+		for days := 7; ; days *= 4 {
+			start := now.AddDate(0, 0, -days)
+			if !afterDay(start, from) {
+				break
+			}
+			prev, err := lastOccurrence(NewIterator(rule, start, from), now)
+			if err != nil || !prev.IsZero() {
+				return prev, err
+			}
+		}
+	}
+
+	prev, err := lastOccurrence(NewIterator(rule, from, from), now)
+	if err != nil || !prev.IsZero() {
+		return prev, err
+	}
+	return from, nil
+}
+
+// lastOccurrence walks the iterator to the last occurrence on or before now, zero time if there are none
+func lastOccurrence(it *Iterator, now time.Time) (time.Time, error) {
+	var prev time.Time
+	for i := 0; i < maxOccurrenceWalk; i++ {
+		next, err := it.Next()
+		if errors.Is(err, ErrNoMoreOccurrences) || err == nil && afterDay(next, now) {
+			return prev, nil
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		prev = next
+	}
+	return time.Time{}, errTooManyOccurrences
+}
+
+// Between returns up to count occurrences of the rule within the range, both ends included.
+// As for count limited rules, the start date is the first occurrence.
+func Between(rule Rule, from, start, end time.Time, count int) ([]time.Time, error) {
+	dates := make([]time.Time, 0, count)
+	if !afterDay(start, from) && !afterDay(from, end) {
+		dates = append(dates, from)
+	}
+
+	now := from
+	// Count limited rules are walked from the start date to count occurrences before the range
+	if OccurrenceCount(rule) == 0 && afterDay(start, from) {
+		now = start.AddDate(0, 0, -1)
+	}
+
+	it := NewIterator(rule, now, from)
+	for walked := 0; len(dates) < count; walked++ {
+		if walked == maxOccurrenceWalk+count {
+			return nil, errTooManyOccurrences
+		}
+		next, err := it.Next()
+		if errors.Is(err, ErrNoMoreOccurrences) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if afterDay(next, end) {
+			break
+		}
+		if !afterDay(start, next) {
+			dates = append(dates, next)
+		}
+	}
+	return dates, nil
+}
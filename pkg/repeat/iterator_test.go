@@ -0,0 +1,117 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrev(t *testing.T) {
+	tbl := []struct {
+		now    string
+		date   string
+		repeat string
+		want   string
+	}{
+		{"20240126", "20240101", "d 7", "20240122"},
+		{"20240110", "20240101", "m 15", "20240101"},
+		{"20240126", "00010101", "d 1", "20240126"},
+		{"20240126", "00010101", "b 1", "20240126"},
+		{"20240128", "19000101", "b 1", "20240126"},
+		{"20240126", "19000101", "n 2 2", "20240109"},
+		{"20240126", "19000101", "c 29 2 *", "20200229"},
+		{"20240126", "19000101", "d 1 until 19500101", "19500101"},
+		{"20240126", "20240101", "d 1 count 5", "20240105"},
+		{"20240126", "20240101", "RRULE:FREQ=DAILY;COUNT=100000", "20240126"},
+	}
+	for _, v := range tbl {
+		now, err := time.Parse(DateFormat, v.now)
+		assert.NoError(t, err)
+		prev, err := PrevDate(now, v.date, v.repeat)
+		if assert.NoError(t, err, "%q %q from %s", v.date, v.repeat, v.now) {
+			assert.Equal(t, v.want, prev, "%q %q from %s", v.date, v.repeat, v.now)
+		}
+	}
+
+	// Count limited rules are walked from the start date
+	now, _ := time.Parse(DateFormat, "20240126")
+	_, err := PrevDate(now, "19000101", "d 1 count 100000")
+	assert.Error(t, err)
+}
+
+func TestBetween(t *testing.T) {
+	parse := func(date string) time.Time {
+		parsed, err := time.Parse(DateFormat, date)
+		assert.NoError(t, err)
+		return parsed
+	}
+	format := func(dates []time.Time) []string {
+		items := []string{}
+		for _, date := range dates {
+			items = append(items, date.Format(DateFormat))
+		}
+		return items
+	}
+
+	rule, err := Parse("b 2")
+	assert.NoError(t, err)
+	dates, err := Between(rule, parse("00010101"), parse("20240122"), parse("20240131"), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"20240123", "20240125", "20240129", "20240131"}, format(dates))
+
+	rule, err = Parse("RRULE:FREQ=DAILY;COUNT=5000")
+	assert.NoError(t, err)
+	dates, err = Between(rule, parse("20000101"), parse("20130907"), parse("20130920"), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"20130907", "20130908"}, format(dates))
+
+	// Too many occurrences before the range
+	rule, err = Parse("d 1 count 100000")
+	assert.NoError(t, err)
+	_, err = Between(rule, parse("19000101"), parse("20240101"), parse("20240131"), 10)
+	assert.Error(t, err)
+}
+
+func TestIteratorCount(t *testing.T) {
+	now, _ := time.Parse(DateFormat, "20240126")
+	for _, v := range []struct {
+		date   string
+		repeat string
+		want   []string
+	}{
+		// The start date after now is the first of the occurrences
+		{"20240129", "w 1 count 3", []string{"20240129", "20240205", "20240212"}},
+		{"20240129", "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3", []string{"20240129", "20240205", "20240212"}},
+		// and it's counted when the rule doesn't return it
+		{"20240129", "d 7 count 3", []string{"20240205", "20240212"}},
+		{"20240129", "RRULE:FREQ=DAILY;INTERVAL=7;COUNT=3", []string{"20240129", "20240205", "20240212"}},
+		// Occurrences before now are counted from the start date
+		{"20240101", "w 1 count 3", nil},
+		{"20240101", "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3", nil},
+		{"20240101", "w 1 count 5", []string{"20240129"}},
+		{"20240101", "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=5", []string{"20240129"}},
+		{"20240101", "RRULE:FREQ=DAILY;COUNT=30", []string{"20240127", "20240128", "20240129", "20240130"}},
+	} {
+		from, _ := time.Parse(DateFormat, v.date)
+		rule, err := Parse(v.repeat)
+		assert.NoError(t, err)
+		dates, err := NewIterator(rule, now, from).Take(10, time.Time{})
+		assert.NoError(t, err)
+		var got []string
+		for _, date := range dates {
+			got = append(got, date.Format(DateFormat))
+		}
+		assert.Equal(t, v.want, got, "%q from %s", v.repeat, v.date)
+	}
+
+	// Occurrences left after the first one after now
+	rule, err := Parse("w 1 count 5")
+	assert.NoError(t, err)
+	from, _ := time.Parse(DateFormat, "20240115")
+	it := NewIterator(rule, now, from)
+	next, err := it.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "20240129", next.Format(DateFormat))
+	assert.Equal(t, 2, it.Left())
+}
@@ -0,0 +1,95 @@
+package repeat
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// limitedRule stops a rule after a date or a number of occurrences:
+// <rule> [until <date>] [count <number>]
+// The occurrence count includes the start date and is tracked by the caller,
+// since a rule itself doesn't know how many times the task was done.
+type limitedRule struct {
+	Rule
+	Until time.Time
+	Count int
+}
+
+// Next returns the next occurrence of the wrapped rule up to the until date
+func (r limitedRule) Next(now, from time.Time) (time.Time, error) {
+	next, err := r.Rule.Next(now, from)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !r.Until.IsZero() && afterDay(next, r.Until) {
+		return time.Time{}, ErrNoMoreOccurrences
+	}
+	return next, nil
+}
+
+func (r limitedRule) String() string {
+	rule := r.Rule.String()
+	if !r.Until.IsZero() {
+		rule += " until " + r.Until.Format(DateFormat)
+	}
+	if r.Count > 0 {
+		rule += " count " + strconv.Itoa(r.Count)
+	}
+	return rule
+}
+
+// OccurrenceCount returns the total number of occurrences allowed by the rule,
+// including the start date, or 0 if the rule is not limited by count
+func OccurrenceCount(rule Rule) int {
+	switch r := rule.(type) {
+	case limitedRule:
+		return r.Count
+	case rrule:
+		return r.Count
+	case exceptionRule:
+		return OccurrenceCount(r.Rule)
+	default:
+		return 0
+	}
+}
+
+// countedUntil returns the number of occurrences of the rule without count from the start date
+// up to now, at most count. The start date is the first occurrence, like DTSTART of RFC 5545,
+// so the count modifier and RRULE COUNT are counted the same way.
+func countedUntil(rule Rule, now, from time.Time, count int) (int, error) {
+	if afterDay(from, now) {
+		return 0, nil
+	}
+
+	counted := 1
+	for date := from; counted < count; counted++ {
+		if counted > maxOccurrenceWalk {
+			return 0, errTooManyOccurrences
+		}
+		next, err := rule.Next(date, from)
+		if errors.Is(err, ErrNoMoreOccurrences) || err == nil && afterDay(next, now) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		date = next
+	}
+	return counted, nil
+}
+
+// withoutCount returns the rule which doesn't count occurrences itself,
+// for callers tracking the count with OccurrenceCount and countedUntil
+func withoutCount(rule Rule) Rule {
+	switch r := rule.(type) {
+	case rrule:
+		r.Count = 0
+		return r
+	case exceptionRule:
+		r.Rule = withoutCount(r.Rule)
+		return r
+	default:
+		return rule
+	}
+}
@@ -0,0 +1,35 @@
+package repeat
+
+import (
+	"errors"
+	"time"
+)
+
+// Repeat modes of a task
+const (
+	// ModeFixed counts the next occurrence from the scheduled date
+	ModeFixed = "fixed"
+	// ModeRelative counts the next occurrence from the completion date
+	ModeRelative = "relative"
+)
+
+// ParseMode validates a repeat mode, empty mode is fixed
+func ParseMode(mode string) (string, error) {
+	switch mode {
+	case "", ModeFixed:
+		return ModeFixed, nil
+	case ModeRelative:
+		return ModeRelative, nil
+	default:
+		return "", errors.New("repeat mode must be fixed or relative")
+	}
+}
+
+// CountFrom returns the date the next occurrence is counted from when the task is done at 'now':
+// the scheduled date in fixed mode, the day of 'now' in relative mode
+func CountFrom(mode string, now, date time.Time) time.Time {
+	if mode != ModeRelative {
+		return date
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, date.Location())
+}
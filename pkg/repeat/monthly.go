@@ -0,0 +1,171 @@
+package repeat
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// monthlyRule repeats on the given days of the month: m <days> [<months>|/<interval>]
+type monthlyRule struct {
+	// Days indices 1-31, 0 is unused
+	Days [32]bool
+	// NegativeDays counted from the end of the month (-1 is the last day)
+	NegativeDays []int
+	// Months indices 1-12, 0 is unused
+	Months [13]bool
+	// AllMonths is set when months are not specified
+	AllMonths bool
+	// Interval in months counted from the month of the start date, 0 if not specified.
+	// Days missing in short months are moved to the last day of the month.
+	Interval int
+}
+
+// monthlyHandler handles 'm' rules
+type monthlyHandler struct{}
+
+// Parse parses monthly repeat rule: m <days> [<months>|/<interval>]
+func (monthlyHandler) Parse(parts []string) (Rule, error) {
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, ruleError(CodeFormat, "invalid monthly rule format")
+	}
+
+	var rule monthlyRule
+
+	// Parse days of the month
+	for _, item := range splitList(parts[1]) {
+		day, err := strconv.Atoi(item.Value)
+		if err != nil {
+			return nil, itemError(CodeValue, 1, item, "invalid day of month")
+		}
+
+		// Check valid day range
+		if day < -31 || day == 0 || day > 31 {
+			return nil, itemError(CodeValue, 1, item, "invalid day of month")
+		}
+
+		if day > 0 {
+			rule.Days[day] = true
+		} else {
+			rule.NegativeDays = append(rule.NegativeDays, day)
+		}
+	}
+
+	if len(parts) == 3 && strings.HasPrefix(parts[2], "/") {
+		interval, err := parseInterval(parts, 2)
+		if err != nil {
+			return nil, err
+		}
+		rule.Interval = interval
+		parts = parts[:2]
+	}
+
+	if len(parts) == 3 {
+		// Parse specified months
+		for _, item := range splitList(parts[2]) {
+			month, err := strconv.Atoi(item.Value)
+			if err != nil || month < 1 || month > 12 {
+				return nil, itemError(CodeValue, 2, item, "invalid month")
+			}
+			rule.Months[month] = true
+		}
+	} else {
+		// If months not specified, use all months
+		rule.AllMonths = true
+		for i := 1; i <= 12; i++ {
+			rule.Months[i] = true
+		}
+	}
+
+	return rule, nil
+}
+
+// Describe describes the monthly rule
+func (monthlyHandler) Describe(rule Rule, lang string) string {
+	return rule.(monthlyRule).describe(lang)
+}
+
+// maxMonthlyMonths protects against rules that never match (February 30).
+// February 29 may be 8 years apart, e.g. 2096 and 2104.
+const maxMonthlyMonths = 12 * 9
+
+// Next jumps to the first day after 'now' and checks month by month for the earliest rule day
+func (r monthlyRule) Next(now, from time.Time) (time.Time, error) {
+	start := firstCandidate(now, from)
+	year, month, minDay := start.Date()
+
+	step := 1
+	if r.Interval > 1 {
+		// Jump to the first month which is a multiple of the interval apart from the start month
+		step = r.Interval
+		months := (year-from.Year())*12 + int(month-from.Month())
+		if offset := months % step; offset != 0 {
+			year, month = addMonths(year, month, step-offset)
+			minDay = 1
+		}
+	}
+
+	for i := 0; i < maxMonthlyMonths; i++ {
+		if r.Months[month] {
+			if day := r.firstDay(year, month, minDay); day != 0 {
+				return time.Date(year, month, day,
+					from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), from.Location()), nil
+			}
+		}
+
+		// Following months are checked from the first day
+		minDay = 1
+		year, month = addMonths(year, month, step)
+	}
+	return time.Time{}, errors.New("cannot find next date")
+}
+
+// firstDay returns the earliest rule day of the month not before minDay, 0 if none
+func (r monthlyRule) firstDay(year int, month time.Month, minDay int) int {
+	lastDayOfMonth := daysIn(year, month)
+	first := 0
+	for day := minDay; day <= 31; day++ {
+		if !r.Days[day] {
+			continue
+		}
+		if day <= lastDayOfMonth {
+			first = day
+		} else if r.Interval > 0 && lastDayOfMonth >= minDay {
+			first = lastDayOfMonth
+		}
+		break
+	}
+	for _, negDay := range r.NegativeDays {
+		day := lastDayOfMonth + negDay + 1
+		if day >= minDay && (first == 0 || day < first) {
+			first = day
+		}
+	}
+	return first
+}
+
+func (r monthlyRule) String() string {
+	days := joinFlags(r.Days[:])
+	for _, negDay := range r.NegativeDays {
+		if days != "" {
+			days += ","
+		}
+		days += strconv.Itoa(negDay)
+	}
+	if r.AllMonths {
+		return "m " + days + intervalString(r.Interval)
+	}
+	return "m " + days + " " + joinFlags(r.Months[:])
+}
+
+// addMonths adds months to the year and month
+func addMonths(year int, month time.Month, months int) (int, time.Month) {
+	total := year*12 + int(month) - 1 + months
+	return total / 12, time.Month(total%12 + 1)
+}
+
+// daysIn returns the number of days in the month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
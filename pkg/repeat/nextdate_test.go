@@ -0,0 +1,169 @@
+package repeat
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// scanNextDate is the day by day implementation of d, y, w and m rules
+// that is used as a reference for the closed-form one
+func scanNextDate(now, from time.Time, rule string) (time.Time, error) {
+	parts := strings.Fields(rule)
+	after := func(date time.Time) bool {
+		return date.Format("20060102") > now.Format("20060102")
+	}
+
+	switch parts[0] {
+	case "d", "y":
+		years, days := 1, 0
+		if parts[0] == "d" {
+			years, days = 0, atoi(parts[1])
+		}
+		date := from
+		for {
+			date = date.AddDate(years, 0, days)
+			if after(date) {
+				return date, nil
+			}
+		}
+	case "w":
+		weekdays := make(map[int]bool)
+		for _, day := range strings.Split(parts[1], ",") {
+			weekdays[atoi(day)%7] = true
+		}
+		date := from
+		for i := 0; i < 1000; i++ {
+			if after(date) && weekdays[int(date.Weekday())] {
+				return date, nil
+			}
+			date = date.AddDate(0, 0, 1)
+		}
+	case "m":
+		months := make(map[time.Month]bool)
+		for month := 1; month <= 12; month++ {
+			months[time.Month(month)] = len(parts) == 2
+		}
+		if len(parts) == 3 {
+			for _, month := range strings.Split(parts[2], ",") {
+				months[time.Month(atoi(month))] = true
+			}
+		}
+		date := from
+		for i := 0; i < 2000; i++ {
+			if after(date) && months[date.Month()] {
+				lastDay := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+				for _, day := range strings.Split(parts[1], ",") {
+					n := atoi(day)
+					if n == date.Day() || (n < 0 && lastDay+n+1 == date.Day()) {
+						return date, nil
+					}
+				}
+			}
+			date = date.AddDate(0, 0, 1)
+		}
+	}
+	return time.Time{}, errors.New("cannot find next date")
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// randomRule generates a random d, y, w or m rule
+func randomRule(rnd *rand.Rand) string {
+	list := func(min, max, size int) string {
+		var items []string
+		for i := 0; i < size; i++ {
+			items = append(items, strconv.Itoa(min+rnd.IntN(max-min+1)))
+		}
+		return strings.Join(items, ",")
+	}
+
+	switch rnd.IntN(4) {
+	case 0:
+		return "d " + strconv.Itoa(1+rnd.IntN(400))
+	case 1:
+		return "y"
+	case 2:
+		return "w " + list(1, 7, 1+rnd.IntN(3))
+	default:
+		days := list(1, 31, 1+rnd.IntN(3))
+		if rnd.IntN(4) == 0 {
+			days += "," + list(-2, -1, 1)
+		}
+		if rnd.IntN(2) == 0 {
+			return "m " + days
+		}
+		return "m " + days + " " + list(1, 12, 1+rnd.IntN(3))
+	}
+}
+
+// randomDate generates a random date between the years
+func randomDate(rnd *rand.Rand, fromYear, toYear int) time.Time {
+	start := time.Date(fromYear, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := int(time.Date(toYear, 1, 1, 0, 0, 0, 0, time.UTC).Sub(start).Hours() / 24)
+	return start.AddDate(0, 0, rnd.IntN(days))
+}
+
+func TestNextDateDifferential(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	var compared, skipped int
+	for i := 0; i < 20000; i++ {
+		rule := randomRule(rnd)
+		from := randomDate(rnd, 1990, 2030)
+		// Most tasks are recent, some are decades old
+		now := from.AddDate(0, 0, rnd.IntN(60)-30)
+		if rnd.IntN(4) == 0 {
+			now = randomDate(rnd, 1990, 2035)
+		}
+
+		want, err := scanNextDate(now, from, rule)
+		if err != nil {
+			// Day by day scanning gives up on old tasks and sparse rules
+			skipped++
+			continue
+		}
+
+		parsed, err := Parse(rule)
+		assert.NoError(t, err, rule)
+		got, err := parsed.Next(now, from)
+		assert.NoError(t, err, rule)
+		if !assert.Equal(t, want.Format("20060102"), got.Format("20060102"),
+			"now=%s date=%s repeat=%q", now.Format("20060102"), from.Format("20060102"), rule) {
+			return
+		}
+		compared++
+	}
+	assert.Greater(t, compared, 10*skipped)
+}
+
+func BenchmarkNextDate(b *testing.B) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	for _, rule := range []string{"d 1", "d 7", "y", "w 7", "m 31", "m -2 2,8"} {
+		parsed, err := Parse(rule)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("closed/%s", rule), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				parsed.Next(now, from)
+			}
+		})
+		b.Run(fmt.Sprintf("scan/%s", rule), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				scanNextDate(now, from, rule)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package repeat
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ordinalRule repeats on the Nth weekdays of the month: n <ordinals> <weekdays> [<months>]
+// Ordinals are 1-5 or -1 for the last weekday of the month,
+// e.g. "n 2 2" is the second Tuesday and "n -1 5" is the last Friday.
+type ordinalRule struct {
+	Ordinals []int
+	// Weekdays indices 1-7 (1=Monday, 7=Sunday), 0 is unused
+	Weekdays [8]bool
+	// Months indices 1-12, 0 is unused
+	Months [13]bool
+	// AllMonths is set when months are not specified
+	AllMonths bool
+}
+
+// ordinalHandler handles 'n' rules
+type ordinalHandler struct{}
+
+// Parse parses ordinal weekday rule: n <ordinals> <weekdays> [<months>]
+func (ordinalHandler) Parse(parts []string) (Rule, error) {
+	if len(parts) < 3 || len(parts) > 4 {
+		return nil, ruleError(CodeFormat, "invalid ordinal weekday rule format")
+	}
+
+	var rule ordinalRule
+
+	// Parse ordinals
+	for _, item := range splitList(parts[1]) {
+		ordinal, err := strconv.Atoi(item.Value)
+		if err != nil || ordinal < -1 || ordinal == 0 || ordinal > 5 {
+			return nil, itemError(CodeValue, 1, item, "ordinal must be between 1 and 5 or -1")
+		}
+		if !slices.Contains(rule.Ordinals, ordinal) {
+			rule.Ordinals = append(rule.Ordinals, ordinal)
+		}
+	}
+
+	// Parse weekdays
+	for _, item := range splitList(parts[2]) {
+		day, err := strconv.Atoi(item.Value)
+		if err != nil {
+			return nil, itemError(CodeValue, 2, item, "invalid weekday")
+		}
+		if day < 1 || day > 7 {
+			return nil, itemError(CodeValue, 2, item, "weekday must be between 1 and 7")
+		}
+		rule.Weekdays[day] = true
+	}
+
+	if len(parts) == 4 {
+		// Parse specified months
+		for _, item := range splitList(parts[3]) {
+			month, err := strconv.Atoi(item.Value)
+			if err != nil || month < 1 || month > 12 {
+				return nil, itemError(CodeValue, 3, item, "invalid month")
+			}
+			rule.Months[month] = true
+		}
+	} else {
+		rule.AllMonths = true
+		for i := 1; i <= 12; i++ {
+			rule.Months[i] = true
+		}
+	}
+
+	return rule, nil
+}
+
+// Describe describes the ordinal rule
+func (ordinalHandler) Describe(rule Rule, lang string) string {
+	return rule.(ordinalRule).describe(lang)
+}
+
+// Next jumps to the month of the first day after 'now' and checks month by month for a matching weekday
+func (r ordinalRule) Next(now, from time.Time) (time.Time, error) {
+	start := firstCandidate(now, from)
+	year, month := start.Year(), start.Month()
+	// A fifth weekday in a single month may occur only once in 28 years
+	for i := 0; i < 12*60; i++ { // protection against infinite loop
+		if r.Months[month] {
+			for _, date := range r.monthDates(year, month, from.Location()) {
+				if !date.Before(from) && afterDay(date, now) {
+					return date, nil
+				}
+			}
+		}
+		month++
+		if month > time.December {
+			month = time.January
+			year++
+		}
+	}
+	return time.Time{}, errors.New("cannot find next date for ordinal weekdays")
+}
+
+// monthDates returns sorted dates of the rule in the given month
+func (r ordinalRule) monthDates(year int, month time.Month, loc *time.Location) []time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	lastDay := daysIn(year, month)
+	lastWeekday := isoWeekday(time.Date(year, month, lastDay, 0, 0, 0, 0, loc))
+
+	var dates []time.Time
+	for weekday := 1; weekday <= 7; weekday++ {
+		if !r.Weekdays[weekday] {
+			continue
+		}
+		for _, ordinal := range r.Ordinals {
+			var day int
+			if ordinal == -1 {
+				day = lastDay - (lastWeekday-weekday+7)%7
+			} else {
+				day = 1 + (weekday-isoWeekday(first)+7)%7 + 7*(ordinal-1)
+			}
+			if day <= lastDay {
+				dates = append(dates, first.AddDate(0, 0, day-1))
+			}
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+func (r ordinalRule) String() string {
+	ordinals := make([]string, 0, len(r.Ordinals))
+	for _, ordinal := range r.Ordinals {
+		ordinals = append(ordinals, strconv.Itoa(ordinal))
+	}
+	rule := "n " + strings.Join(ordinals, ",") + " " + joinFlags(r.Weekdays[:])
+	if r.AllMonths {
+		return rule
+	}
+	return rule + " " + joinFlags(r.Months[:])
+}
@@ -0,0 +1,34 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrdinalNext(t *testing.T) {
+	tbl := []struct {
+		now    string
+		date   string
+		repeat string
+		want   string
+	}{
+		{"20240126", "20240101", "n 2 2", "20240213"},
+		{"20240126", "20240301", "n 2 2", "20240312"},
+		{"20240126", "20240101", "n -1 5", "20240223"},
+		{"20240125", "20240101", "n -1 5", "20240126"},
+		{"20240126", "20240101", "n 5 4 2,5", "20240229"},
+		// Tasks started long ago are not limited by the search
+		{"20261018", "19500101", "n 2 2", "20261110"},
+		{"20261018", "18000101", "n 5 4 2", "20520229"},
+	}
+	for _, v := range tbl {
+		now, err := time.Parse(DateFormat, v.now)
+		assert.NoError(t, err)
+		next, err := NextDate(now, v.date, v.repeat)
+		if assert.NoError(t, err, "%q %q from %s", v.date, v.repeat, v.now) {
+			assert.Equal(t, v.want, next, "%q %q from %s", v.date, v.repeat, v.now)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Package repeat parses task repeat rules and computes their next occurrences.
+// It has no dependencies on the HTTP layer, so it can be used by tools as well.
+package repeat
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateFormat is the date layout used by tasks
+const DateFormat = "20060102"
+
+// ErrNoMoreOccurrences is returned when a rule has ended
+var ErrNoMoreOccurrences = errors.New("no more occurrences")
+
+// Rule is a parsed repeat rule
+type Rule interface {
+	// Next returns the next occurrence after now, counting from the start date
+	Next(now, from time.Time) (time.Time, error)
+	// String returns the rule in its canonical text form
+	String() string
+}
+
+// Parse parses a repeat rule string. Invalid rules give *RuleError.
+func Parse(repeat string) (Rule, error) {
+	// Check if repeat rule is empty
+	if repeat == "" {
+		return nil, ruleError(CodeEmpty, "empty repeat rule")
+	}
+
+	// Several rules may be combined with ';'
+	if items := splitUnion(repeat); len(items) > 1 {
+		return parseUnion(repeat, items)
+	}
+	return parseRule(repeat)
+}
+
+// parseRule parses a single repeat rule
+func parseRule(repeat string) (Rule, error) {
+	// A user-defined preset stands for the whole rule or one of combined rules
+	if rule, ok := lookupPreset(strings.TrimSpace(repeat)); ok {
+		return Parse(rule)
+	}
+	repeat = expandAlias(repeat)
+
+	// iCalendar rules have their own syntax
+	if isRRule(repeat) {
+		rule, err := parseRRule(repeat)
+		if err != nil {
+			return nil, locate(err, repeat, nil)
+		}
+		return rule, nil
+	}
+
+	// Split the repeat rule into parts
+	tokens := tokenize(repeat)
+	if len(tokens) == 0 {
+		return nil, locate(ruleError(CodeEmpty, "invalid repeat rule format"), repeat, nil)
+	}
+	parts := make([]string, len(tokens))
+	for i, t := range tokens {
+		parts[i] = t.Value
+	}
+
+	// Split off modifiers
+	parts, mods, err := parseModifiers(parts)
+	if err != nil {
+		return nil, locate(err, repeat, tokens)
+	}
+
+	rule, err := parseRuleParts(parts)
+	if err != nil {
+		return nil, locate(err, repeat, tokens)
+	}
+	// Habit tasks are active every day until the quota is met, so they can't be shifted or limited
+	if isHabit(rule) && len(parts) < len(tokens) {
+		return nil, locate(partError(CodeConflict, len(parts), "habit rule can't be combined with modifiers"), repeat, tokens)
+	}
+	if mods.Shift != "" {
+		rule = workdayRule{Rule: rule, Shift: mods.Shift}
+	}
+	if !mods.Until.IsZero() || mods.Count != 0 {
+		rule = limitedRule{Rule: rule, Until: mods.Until, Count: mods.Count}
+	}
+	return rule, nil
+}
+
+// modifiers are optional trailing parts of a rule:
+// [workday next|prev|skip|nearest] [until <date>] [count <number>]
+type modifiers struct {
+	Shift string
+	Until time.Time
+	Count int
+}
+
+// parseModifiers splits trailing modifiers from the rule parts
+func parseModifiers(parts []string) ([]string, modifiers, error) {
+	var mods modifiers
+
+	for len(parts) >= 3 {
+		keyword, value := parts[len(parts)-2], parts[len(parts)-1]
+		keywordPart, valuePart := len(parts)-2, len(parts)-1
+		switch keyword {
+		case "until":
+			if !mods.Until.IsZero() {
+				return nil, mods, partError(CodeDuplicate, keywordPart, "duplicate until condition")
+			}
+			date, err := time.Parse(DateFormat, value)
+			if err != nil {
+				return nil, mods, partError(CodeValue, valuePart, "invalid until date")
+			}
+			mods.Until = date
+		case "count":
+			if mods.Count != 0 {
+				return nil, mods, partError(CodeDuplicate, keywordPart, "duplicate count condition")
+			}
+			number, err := strconv.Atoi(value)
+			if err != nil || number <= 0 {
+				return nil, mods, partError(CodeValue, valuePart, "invalid occurrence count")
+			}
+			mods.Count = number
+		case "workday":
+			if mods.Shift != "" {
+				return nil, mods, partError(CodeDuplicate, keywordPart, "duplicate workday modifier")
+			}
+			if value != shiftNext && value != shiftPrev && value != shiftSkip && value != shiftNearest {
+				return nil, mods, partError(CodeValue, valuePart, "workday modifier must be next, prev, skip or nearest")
+			}
+			mods.Shift = value
+		default:
+			return parts, mods, nil
+		}
+		parts = parts[:len(parts)-2]
+	}
+	return parts, mods, nil
+}
+
+// parseRuleParts parses the rule with the handler of its type
+func parseRuleParts(parts []string) (Rule, error) {
+	handler, ok := lookupHandler(parts[0])
+	if !ok {
+		return nil, partError(CodeUnknownType, 0, "unsupported repeat rule")
+	}
+
+	rule, err := handler.Parse(parts)
+	if err != nil {
+		return nil, err
+	}
+	return handledRule{Rule: rule, handler: handler}, nil
+}
+
+// Validate parses the rule and checks it has a next date for a task starting on 'now'.
+// Rules which never occur, e.g. February 30, give *RuleError with CodeNoOccurrences.
+// An ended rule may have occurred before, so it is valid.
+func Validate(repeat string, now time.Time) error {
+	rule, err := Parse(repeat)
+	if err != nil {
+		return err
+	}
+	if _, err := rule.Next(now, now); err != nil && !errors.Is(err, ErrNoMoreOccurrences) {
+		return &RuleError{Code: CodeNoOccurrences, Message: err.Error(), Token: repeat, part: -1}
+	}
+	return nil
+}
+
+// NextDate calculates the next execution date for a task in text form
+func NextDate(now time.Time, dateStr string, repeat string) (string, error) {
+	// Parse the start date
+	date, err := time.Parse(DateFormat, dateStr)
+	if err != nil {
+		return "", errors.New("invalid start date format")
+	}
+
+	rule, err := Parse(repeat)
+	if err != nil {
+		return "", err
+	}
+
+	next, err := NewIterator(rule, now, date).Next()
+	if err != nil {
+		return "", err
+	}
+	return next.Format(DateFormat), nil
+}
+
+// PrevDate calculates the last execution date on or before now for a task in text form
+func PrevDate(now time.Time, dateStr string, repeat string) (string, error) {
+	// Parse the start date
+	date, err := time.Parse(DateFormat, dateStr)
+	if err != nil {
+		return "", errors.New("invalid start date format")
+	}
+
+	rule, err := Parse(repeat)
+	if err != nil {
+		return "", err
+	}
+
+	prev, err := Prev(rule, now, date)
+	if err != nil {
+		return "", err
+	}
+	return prev.Format(DateFormat), nil
+}
+
+// afterDay checks if a date is strictly after another date (ignoring time)
+func afterDay(date, now time.Time) bool {
+	dateYear, dateMonth, dateDay := date.Date()
+	nowYear, nowMonth, nowDay := now.Date()
+
+	if dateYear != nowYear {
+		return dateYear > nowYear
+	}
+	if dateMonth != nowMonth {
+		return dateMonth > nowMonth
+	}
+	return dateDay > nowDay
+}
+
+// maxInterval is the largest interval of weekly and monthly rules
+const maxInterval = 100
+
+// parseInterval parses interval of weekly and monthly rules in the given part: /<number>
+func parseInterval(parts []string, part int) (int, error) {
+	numberStr, ok := strings.CutPrefix(parts[part], "/")
+	if !ok {
+		return 0, partError(CodeValue, part, "interval must start with /")
+	}
+	interval, err := strconv.Atoi(numberStr)
+	if err != nil || interval < 1 || interval > maxInterval {
+		return 0, partError(CodeValue, part, "invalid interval")
+	}
+	return interval, nil
+}
+
+// intervalString returns interval in rule text form, empty if not specified
+func intervalString(interval int) string {
+	if interval == 0 {
+		return ""
+	}
+	return " /" + strconv.Itoa(interval)
+}
+
+// firstCandidate returns the start date, or the day after 'now' if the start date is not later
+func firstCandidate(now, from time.Time) time.Time {
+	if afterDay(from, now) {
+		return from
+	}
+	return from.AddDate(0, 0, daysBetween(from, now)+1)
+}
+
+// isoWeekday returns weekday number 1-7, where 1=Monday, 7=Sunday
+func isoWeekday(date time.Time) int {
+	weekday := int(date.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return weekday
+}
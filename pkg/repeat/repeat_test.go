@@ -0,0 +1,172 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nextDateCases are results of NextDate before the rules were moved to this package,
+// an empty result means an error
+var nextDateCases = []struct {
+	now    string
+	date   string
+	repeat string
+	want   string
+}{
+	{"20240126", "20240126", "", ""},
+	{"20240126", "20240126", "k 34", ""},
+	{"20240126", "20240126", "ooops", ""},
+	{"20240126", "ooops", "y", ""},
+	{"20240126", "15000156", "y", ""},
+	{"20240126", "16890220", "y", "20240220"},
+	{"20240126", "20250701", "y", "20260701"},
+	{"20240126", "20240101", "y", "20250101"},
+	{"20240126", "20231231", "y", "20241231"},
+	{"20240126", "20240229", "y", "20250301"},
+	{"20240126", "20200229", "y", "20240301"},
+	{"20240126", "20240301", "y", "20250301"},
+	{"20240126", "20240126", "y 1", ""},
+	{"20240126", "20240113", "d", ""},
+	{"20240126", "20240113", "d 7", "20240127"},
+	{"20240126", "20240120", "d 20", "20240209"},
+	{"20240126", "20240202", "d 30", "20240303"},
+	{"20240126", "20240320", "d 401", ""},
+	{"20240126", "20231225", "d 12", "20240130"},
+	{"20240126", "20240228", "d 1", "20240229"},
+	{"20240126", "20240126", "d 1", "20240127"},
+	{"20240126", "20240126", "d 0", ""},
+	{"20240126", "20240126", "d x", ""},
+	{"20240126", "20240126", "d 1 2", ""},
+	{"20240126", "20231106", "m 13", "20240213"},
+	{"20240126", "20240120", "m 40,11,19", ""},
+	{"20240126", "20240116", "m 16,5", "20240205"},
+	{"20240126", "20240126", "m 25,26,7", "20240207"},
+	{"20240126", "20240409", "m 31", "20240531"},
+	{"20240126", "20240329", "m 10,17 12,8,1", "20240810"},
+	{"20240126", "20230311", "m 07,19 05,6", "20240507"},
+	{"20240126", "20230311", "m 1 1,2", "20240201"},
+	{"20240126", "20240127", "m -1", "20240131"},
+	{"20240126", "20240222", "m -2", "20240228"},
+	{"20240126", "20240326", "m -1,-2", "20240330"},
+	{"20240126", "20240201", "m -1,18", "20240218"},
+	{"20240126", "20240101", "m 30 2", ""},
+	{"20240126", "20240101", "m 1 13", ""},
+	{"20240126", "20240101", "m", ""},
+	{"20240126", "20240125", "w 1,2,3", "20240129"},
+	{"20240126", "20240126", "w 7", "20240128"},
+	{"20240126", "20230126", "w 4,5", "20240201"},
+	{"20240126", "20230226", "w 8,4,5", ""},
+	{"20240126", "20240126", "w 0", ""},
+	{"20240126", "20240126", "w", ""},
+	{"20240126", "20250101", "w 1", "20250106"},
+	{"20250315", "20240126", "", ""},
+	{"20250315", "20240126", "k 34", ""},
+	{"20250315", "20240126", "ooops", ""},
+	{"20250315", "ooops", "y", ""},
+	{"20250315", "15000156", "y", ""},
+	{"20250315", "16890220", "y", "20260220"},
+	{"20250315", "20250701", "y", "20260701"},
+	{"20250315", "20240101", "y", "20260101"},
+	{"20250315", "20231231", "y", "20251231"},
+	{"20250315", "20240229", "y", "20260301"},
+	{"20250315", "20200229", "y", "20260301"},
+	{"20250315", "20240301", "y", "20260301"},
+	{"20250315", "20240126", "y 1", ""},
+	{"20250315", "20240113", "d", ""},
+	{"20250315", "20240113", "d 7", "20250322"},
+	{"20250315", "20240120", "d 20", "20250404"},
+	{"20250315", "20240202", "d 30", "20250328"},
+	{"20250315", "20240320", "d 401", ""},
+	{"20250315", "20231225", "d 12", "20250325"},
+	{"20250315", "20240228", "d 1", "20250316"},
+	{"20250315", "20240126", "d 1", "20250316"},
+	{"20250315", "20240126", "d 0", ""},
+	{"20250315", "20240126", "d x", ""},
+	{"20250315", "20240126", "d 1 2", ""},
+	{"20250315", "20231106", "m 13", "20250413"},
+	{"20250315", "20240120", "m 40,11,19", ""},
+	{"20250315", "20240116", "m 16,5", "20250316"},
+	{"20250315", "20240126", "m 25,26,7", "20250325"},
+	{"20250315", "20240409", "m 31", "20250331"},
+	{"20250315", "20240329", "m 10,17 12,8,1", "20250810"},
+	{"20250315", "20230311", "m 07,19 05,6", "20250507"},
+	{"20250315", "20230311", "m 1 1,2", "20260101"},
+	{"20250315", "20240127", "m -1", "20250331"},
+	{"20250315", "20240222", "m -2", "20250330"},
+	{"20250315", "20240326", "m -1,-2", "20250330"},
+	{"20250315", "20240201", "m -1,18", "20250318"},
+	{"20250315", "20240101", "m 30 2", ""},
+	{"20250315", "20240101", "m 1 13", ""},
+	{"20250315", "20240101", "m", ""},
+	{"20250315", "20240125", "w 1,2,3", "20250317"},
+	{"20250315", "20240126", "w 7", "20250316"},
+	{"20250315", "20230126", "w 4,5", "20250320"},
+	{"20250315", "20230226", "w 8,4,5", ""},
+	{"20250315", "20240126", "w 0", ""},
+	{"20250315", "20240126", "w", ""},
+	{"20250315", "20250101", "w 1", "20250317"},
+}
+
+func TestNextDate(t *testing.T) {
+	for _, v := range nextDateCases {
+		now, err := time.Parse(DateFormat, v.now)
+		assert.NoError(t, err)
+
+		next, err := NextDate(now, v.date, v.repeat)
+		if v.want == "" {
+			assert.Error(t, err, "%q %q from %s", v.date, v.repeat, v.now)
+			continue
+		}
+		if assert.NoError(t, err, "%q %q from %s", v.date, v.repeat, v.now) {
+			assert.Equal(t, v.want, next, "%q %q from %s", v.date, v.repeat, v.now)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tbl := []struct {
+		repeat string
+		want   string
+	}{
+		{"d 7", "d 7"},
+		{"y", "y"},
+		{"w 3,1,2", "w 1,2,3"},
+		{"m 7,25,26", "m 7,25,26"},
+		{"m 1 2,1", "m 1 1,2"},
+		{"m -1,18", "m 18,-1"},
+	}
+	for _, v := range tbl {
+		rule, err := Parse(v.repeat)
+		if assert.NoError(t, err, v.repeat) {
+			assert.Equal(t, v.want, rule.String(), v.repeat)
+		}
+	}
+
+	for _, repeat := range []string{"", "k 34", "d", "d 0", "d 401", "y 1", "w 0", "w 8,4,5", "m", "m 40,11,19", "m 1 13"} {
+		_, err := Parse(repeat)
+		assert.Error(t, err, repeat)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now, _ := time.Parse(DateFormat, "20240126")
+	for repeat, code := range map[string]string{
+		"m 31":               "",
+		"d 5 until 20200101": "",
+		"m 30 2":             CodeNoOccurrences,
+		"c 31 2 *":           CodeNoOccurrences,
+		"m 40":               CodeValue,
+	} {
+		err := Validate(repeat, now)
+		if code == "" {
+			assert.NoError(t, err, repeat)
+			continue
+		}
+		var ruleErr *RuleError
+		if assert.ErrorAs(t, err, &ruleErr, repeat) {
+			assert.Equal(t, code, ruleErr.Code, repeat)
+		}
+	}
+}
@@ -0,0 +1,483 @@
+package repeat
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rrulePrefix starts iCalendar (RFC 5545) recurrence rules
+const rrulePrefix = "RRULE:"
+
+// Recurrence frequencies supported in RRULE
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+	freqYearly  = "YEARLY"
+)
+
+// maxRRulePeriods protects against infinite loop while searching periods
+const maxRRulePeriods = 100000
+
+// weekdayCodes maps RRULE weekday codes to weekday numbers 1-7
+var weekdayCodes = map[string]int{
+	"MO": 1, "TU": 2, "WE": 3, "TH": 4, "FR": 5, "SA": 6, "SU": 7,
+}
+
+// byDay is an RRULE weekday with an optional ordinal, e.g. 2TU or -1FR
+type byDay struct {
+	Ordinal int
+	Weekday int
+}
+
+// rrule is the common subset of an iCalendar recurrence rule:
+// RRULE:FREQ=...;INTERVAL=...;BYDAY=...;BYMONTHDAY=...;BYMONTH=...;COUNT=...;UNTIL=...
+// The task date is the start of the recurrence (DTSTART).
+type rrule struct {
+	Freq       string
+	Interval   int
+	ByDay      []byDay
+	ByMonthDay []int
+	// ByMonth indices 1-12, 0 is unused
+	ByMonth    [13]bool
+	HasByMonth bool
+	Count      int
+	Until      time.Time
+}
+
+// isRRule checks if the repeat rule is an RRULE string
+func isRRule(repeat string) bool {
+	return strings.HasPrefix(strings.ToUpper(repeat), rrulePrefix)
+}
+
+// parseRRule parses an RRULE string
+func parseRRule(repeat string) (Rule, error) {
+	trimmed := strings.TrimSpace(repeat)
+	bodyOffset := strings.Index(repeat, trimmed) + len(rrulePrefix)
+	body := trimmed[len(rrulePrefix):]
+	if body == "" {
+		return nil, ruleError(CodeFormat, "empty RRULE")
+	}
+
+	rule := rrule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range splitBy(body, ";") {
+		offset := bodyOffset + part.Offset
+		fail := func(code, message string) error {
+			return tokenError(code, part.Value, offset, message)
+		}
+
+		key, value, ok := strings.Cut(part.Value, "=")
+		if !ok || value == "" {
+			return nil, fail(CodeFormat, "invalid RRULE part: "+part.Value)
+		}
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return nil, fail(CodeDuplicate, "duplicate RRULE part: "+key)
+		}
+		seen[key] = true
+
+		// failItem points to an item of the value list
+		valueOffset := offset + len(key) + 1
+		failItem := func(item listItem, message string) error {
+			return tokenError(CodeValue, item.Value, valueOffset+item.Offset, message)
+		}
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(value)
+			switch rule.Freq {
+			case freqDaily, freqWeekly, freqMonthly, freqYearly:
+			default:
+				return nil, fail(CodeValue, "unsupported RRULE frequency")
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+			if err != nil || rule.Interval <= 0 || rule.Interval > 1000 {
+				return nil, fail(CodeValue, "invalid RRULE interval")
+			}
+		case "BYDAY":
+			for _, item := range splitList(value) {
+				day, err := parseByDay(item.Value)
+				if err != nil {
+					return nil, failItem(item, err.Error())
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, item := range splitList(value) {
+				day, err := strconv.Atoi(item.Value)
+				if err != nil || day < -31 || day == 0 || day > 31 {
+					return nil, failItem(item, "invalid RRULE month day")
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, day)
+			}
+		case "BYMONTH":
+			rule.HasByMonth = true
+			for _, item := range splitList(value) {
+				month, err := strconv.Atoi(item.Value)
+				if err != nil || month < 1 || month > 12 {
+					return nil, failItem(item, "invalid RRULE month")
+				}
+				rule.ByMonth[month] = true
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+			if err != nil || rule.Count <= 0 {
+				return nil, fail(CodeValue, "invalid RRULE count")
+			}
+		case "UNTIL":
+			// Only the date part of UNTIL is used
+			if len(value) < len(DateFormat) {
+				return nil, fail(CodeValue, "invalid RRULE until date")
+			}
+			rule.Until, err = time.Parse(DateFormat, value[:len(DateFormat)])
+			if err != nil {
+				return nil, fail(CodeValue, "invalid RRULE until date")
+			}
+		case "WKST":
+			// Weeks always start on Monday
+			if strings.ToUpper(value) != "MO" {
+				return nil, fail(CodeValue, "unsupported RRULE week start")
+			}
+		default:
+			return nil, fail(CodeUnknownType, "unsupported RRULE part: "+key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, ruleError(CodeFormat, "RRULE frequency is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, ruleError(CodeConflict, "RRULE count and until can't be used together")
+	}
+	return rule, nil
+}
+
+// parseByDay parses an RRULE weekday, e.g. MO, 2TU or -1FR
+func parseByDay(value string) (byDay, error) {
+	item := strings.ToUpper(value)
+	if len(item) < 2 {
+		return byDay{}, errors.New("invalid RRULE weekday")
+	}
+	weekday, ok := weekdayCodes[item[len(item)-2:]]
+	if !ok {
+		return byDay{}, errors.New("invalid RRULE weekday")
+	}
+
+	var ordinal int
+	if ordinalStr := item[:len(item)-2]; ordinalStr != "" {
+		var err error
+		ordinal, err = strconv.Atoi(ordinalStr)
+		if err != nil || ordinal < -5 || ordinal == 0 || ordinal > 5 {
+			return byDay{}, errors.New("invalid RRULE weekday ordinal")
+		}
+	}
+	return byDay{Ordinal: ordinal, Weekday: weekday}, nil
+}
+
+// Next checks period by period from the start date for the first occurrence after now
+func (r rrule) Next(now, from time.Time) (time.Time, error) {
+	// Without count the periods before now may be skipped
+	var first int
+	if r.Count == 0 {
+		first = r.periodsBetween(from, now)/r.Interval - 1
+		if first < 0 {
+			first = 0
+		}
+	}
+
+	var counted int
+	for k := first; k < first+maxRRulePeriods; k++ {
+		for _, date := range r.periodDates(from, k*r.Interval) {
+			if date.Before(from) {
+				continue
+			}
+			if r.Count > 0 {
+				counted++
+				if counted > r.Count {
+					return time.Time{}, ErrNoMoreOccurrences
+				}
+			}
+			if !r.Until.IsZero() && afterDay(date, r.Until) {
+				return time.Time{}, ErrNoMoreOccurrences
+			}
+			if afterDay(date, now) {
+				return date, nil
+			}
+		}
+	}
+	return time.Time{}, errors.New("cannot find next date for RRULE")
+}
+
+// periodsBetween returns the number of whole frequency units from the start date to now
+func (r rrule) periodsBetween(from, now time.Time) int {
+	switch r.Freq {
+	case freqDaily:
+		return daysBetween(from, now)
+	case freqWeekly:
+		return daysBetween(weekStart(from), now) / 7
+	case freqMonthly:
+		return (now.Year()-from.Year())*12 + int(now.Month()-from.Month())
+	default:
+		return now.Year() - from.Year()
+	}
+}
+
+// periodDates returns sorted candidate dates of the period shifted by offset units from the start date
+func (r rrule) periodDates(from time.Time, offset int) []time.Time {
+	var dates []time.Time
+	switch r.Freq {
+	case freqDaily:
+		date := from.AddDate(0, 0, offset)
+		if r.monthMatches(date.Month()) && r.dayMatches(date) {
+			dates = append(dates, date)
+		}
+	case freqWeekly:
+		start := weekStart(from).AddDate(0, 0, 7*offset)
+		for weekday := 1; weekday <= 7; weekday++ {
+			date := start.AddDate(0, 0, weekday-1)
+			if r.weeklyDayMatches(from, weekday) && r.monthMatches(date.Month()) {
+				dates = append(dates, date)
+			}
+		}
+	case freqMonthly:
+		start := time.Date(from.Year(), from.Month()+time.Month(offset), 1, 0, 0, 0, 0, from.Location())
+		if r.monthMatches(start.Month()) {
+			dates = r.monthDates(from, start)
+		}
+	default:
+		for month := time.January; month <= time.December; month++ {
+			if (r.HasByMonth && r.ByMonth[month]) || (!r.HasByMonth && month == from.Month()) {
+				start := time.Date(from.Year()+offset, month, 1, 0, 0, 0, 0, from.Location())
+				dates = append(dates, r.monthDates(from, start)...)
+			}
+		}
+	}
+	return dates
+}
+
+// monthDates returns sorted dates of the rule in the month starting at start
+func (r rrule) monthDates(from, start time.Time) []time.Time {
+	lastDay := daysIn(start.Year(), start.Month())
+
+	// Without day parts the day of the start date is used
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if from.Day() > lastDay {
+			return nil
+		}
+		return []time.Time{start.AddDate(0, 0, from.Day()-1)}
+	}
+
+	var dates []time.Time
+	for day := 1; day <= lastDay; day++ {
+		date := start.AddDate(0, 0, day-1)
+		if r.dayMatches(date) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// monthMatches checks the month against BYMONTH
+func (r rrule) monthMatches(month time.Month) bool {
+	return !r.HasByMonth || r.ByMonth[month]
+}
+
+// weeklyDayMatches checks the weekday against BYDAY or the start date weekday
+func (r rrule) weeklyDayMatches(from time.Time, weekday int) bool {
+	if len(r.ByDay) == 0 {
+		return isoWeekday(from) == weekday
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// dayMatches checks the date against BYMONTHDAY and BYDAY
+func (r rrule) dayMatches(date time.Time) bool {
+	day := date.Day()
+	lastDay := daysIn(date.Year(), date.Month())
+
+	if len(r.ByMonthDay) > 0 {
+		matched := false
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay == day || lastDay+monthDay+1 == day {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.ByDay) > 0 {
+		weekday := isoWeekday(date)
+		for _, item := range r.ByDay {
+			if item.Weekday != weekday {
+				continue
+			}
+			switch {
+			case item.Ordinal == 0 || r.Freq == freqDaily || r.Freq == freqWeekly:
+				return true
+			case item.Ordinal > 0 && (day-1)/7+1 == item.Ordinal:
+				return true
+			case item.Ordinal < 0 && (lastDay-day)/7+1 == -item.Ordinal:
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r rrule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			days = append(days, byDayString(day))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.HasByMonth {
+		parts = append(parts, "BYMONTH="+joinFlags(r.ByMonth[:]))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format(DateFormat))
+	}
+	return rrulePrefix + strings.Join(parts, ";")
+}
+
+// byDayString formats RRULE weekday with its ordinal
+func byDayString(day byDay) string {
+	code := weekdayCode(day.Weekday)
+	if day.Ordinal == 0 {
+		return code
+	}
+	return strconv.Itoa(day.Ordinal) + code
+}
+
+// weekdayCode returns RRULE code for weekday number 1-7
+func weekdayCode(weekday int) string {
+	for code, number := range weekdayCodes {
+		if number == weekday {
+			return code
+		}
+	}
+	return ""
+}
+
+// errNoRRule is returned for rules which RRULE can't express
+var errNoRRule = errors.New("rule can't be converted to RRULE")
+
+// ToRRule converts a d/w/m/y/n repeat rule with until or count to an equivalent RRULE string.
+// Rules which RRULE can't express, e.g. February 29 policies, give an error.
+// A plain yearly rule started on February 29 moves to March 1, while RRULE skips non-leap years.
+func ToRRule(repeat string) (string, error) {
+	rule, err := Parse(repeat)
+	if err != nil {
+		return "", err
+	}
+
+	converted := rrule{Interval: 1}
+	if r, ok := rule.(limitedRule); ok {
+		// RRULE can't have both
+		if !r.Until.IsZero() && r.Count > 0 {
+			return "", errNoRRule
+		}
+		rule, converted.Until, converted.Count = r.Rule, r.Until, r.Count
+	}
+
+	switch r := unwrapHandled(rule).(type) {
+	case rrule:
+		return r.String(), nil
+	case dailyRule:
+		converted.Freq = freqDaily
+		converted.Interval = r.Interval
+	case yearlyRule:
+		// RRULE skips February 29 in non-leap years, other policies move it
+		if r.Policy != "" && r.Policy != leapSkip {
+			return "", errNoRRule
+		}
+		converted.Freq = freqYearly
+	case weeklyRule:
+		converted.Freq = freqWeekly
+		converted.Interval = max(1, r.Interval)
+		for weekday := 1; weekday <= 7; weekday++ {
+			if r.Weekdays[weekday] {
+				converted.ByDay = append(converted.ByDay, byDay{Weekday: weekday})
+			}
+		}
+	case monthlyRule:
+		converted.Freq = freqMonthly
+		converted.Interval = max(1, r.Interval)
+		negativeDays := r.NegativeDays
+		for day := 1; day <= 31; day++ {
+			if !r.Days[day] {
+				continue
+			}
+			switch {
+			case day <= 28 || r.Interval == 0:
+				converted.ByMonthDay = append(converted.ByMonthDay, day)
+			case day == 31:
+				// Missing days of rules with interval move to the last day of the month
+				if !slices.Contains(negativeDays, -1) {
+					negativeDays = append(slices.Clone(negativeDays), -1)
+				}
+			default:
+				// RRULE skips missing days instead of moving them to the end of the month
+				return "", errNoRRule
+			}
+		}
+		converted.ByMonthDay = append(converted.ByMonthDay, negativeDays...)
+		converted.ByMonth, converted.HasByMonth = r.Months, !r.AllMonths
+	case ordinalRule:
+		converted.Freq = freqMonthly
+		for weekday := 1; weekday <= 7; weekday++ {
+			if !r.Weekdays[weekday] {
+				continue
+			}
+			for _, ordinal := range r.Ordinals {
+				converted.ByDay = append(converted.ByDay, byDay{Ordinal: ordinal, Weekday: weekday})
+			}
+		}
+		converted.ByMonth, converted.HasByMonth = r.Months, !r.AllMonths
+	default:
+		return "", errNoRRule
+	}
+	return converted.String(), nil
+}
+
+// daysBetween returns the number of calendar days from one date to another
+func daysBetween(from, to time.Time) int {
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int((toDay.Unix() - fromDay.Unix()) / (24 * 60 * 60))
+}
+
+// weekStart returns Monday of the date's week
+func weekStart(date time.Time) time.Time {
+	return date.AddDate(0, 0, 1-isoWeekday(date))
+}
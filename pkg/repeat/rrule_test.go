@@ -0,0 +1,71 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToRRule(t *testing.T) {
+	// occurrences returns the next dates of the rule after the start date
+	occurrences := func(repeat string, from time.Time) []string {
+		rule, err := Parse(repeat)
+		if !assert.NoError(t, err, repeat) {
+			return nil
+		}
+		dates, err := NewIterator(rule, from, from).Take(8, time.Time{})
+		assert.NoError(t, err, repeat)
+		var items []string
+		for _, date := range dates {
+			items = append(items, date.Format(DateFormat))
+		}
+		return items
+	}
+
+	for _, v := range []struct {
+		repeat string
+		want   string
+		starts []string
+	}{
+		{"d 7", "RRULE:FREQ=DAILY;INTERVAL=7", []string{"20240110", "20240229"}},
+		{"d 7 until 20240301", "RRULE:FREQ=DAILY;INTERVAL=7;UNTIL=20240301", []string{"20240110", "20240229"}},
+		{"w 1,3 /2", "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", []string{"20240110", "20240115"}},
+		{"w 1 count 3", "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3", []string{"20240110", "20240115"}},
+		{"m 15 /2", "RRULE:FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=15", []string{"20240110", "20240131"}},
+		{"m 31 /3", "RRULE:FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=-1", []string{"20240110", "20240131"}},
+		{"m 1,31 /1", "RRULE:FREQ=MONTHLY;BYMONTHDAY=1,-1", []string{"20240110"}},
+		{"m 31", "RRULE:FREQ=MONTHLY;BYMONTHDAY=31", []string{"20240110"}},
+		{"m -1 2,8", "RRULE:FREQ=MONTHLY;BYMONTHDAY=-1;BYMONTH=2,8", []string{"20240110"}},
+		{"n 2 2", "RRULE:FREQ=MONTHLY;BYDAY=2TU", []string{"20240110"}},
+		{"y", "RRULE:FREQ=YEARLY", []string{"20240110", "20240301"}},
+		{"y skip", "RRULE:FREQ=YEARLY", []string{"20240229"}},
+		{"y skip count 3", "RRULE:FREQ=YEARLY;COUNT=3", []string{"20240229"}},
+		{"daily", "RRULE:FREQ=DAILY", []string{"20240110"}},
+	} {
+		converted, err := ToRRule(v.repeat)
+		if !assert.NoError(t, err, v.repeat) {
+			continue
+		}
+		assert.Equal(t, v.want, converted, v.repeat)
+		for _, start := range v.starts {
+			from, _ := time.Parse(DateFormat, start)
+			assert.Equal(t, occurrences(v.repeat, from), occurrences(converted, from), "%q from %s", v.repeat, start)
+		}
+	}
+
+	for _, repeat := range []string{
+		// February 29 policies other than skip
+		"y clamp",
+		"y roll",
+		// RRULE skips days missing in a month
+		"m 30 /2",
+		"d 7 until 20250101 count 3",
+		"b 1",
+		"w 1 workday next",
+		"m 1; m 15",
+	} {
+		_, err := ToRRule(repeat)
+		assert.Error(t, err, repeat)
+	}
+}
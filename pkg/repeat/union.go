@@ -0,0 +1,101 @@
+package repeat
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// unionSeparator separates combined rules
+const unionSeparator = ";"
+
+// unionRule repeats on the dates of any of its rules: <rule>; <rule>...
+type unionRule struct {
+	Rules []Rule
+}
+
+// splitUnion splits combined rules, keeping KEY=VALUE parts of RRULE together
+func splitUnion(repeat string) []listItem {
+	var items []listItem
+	for _, item := range splitBy(repeat, unionSeparator) {
+		if len(items) > 0 && strings.Contains(item.Value, "=") && !isRRule(strings.TrimSpace(item.Value)) &&
+			isRRule(strings.TrimSpace(items[len(items)-1].Value)) {
+			items[len(items)-1].Value += unionSeparator + item.Value
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// parseUnion parses combined rules, error offsets are counted from the start of the whole rule
+func parseUnion(repeat string, items []listItem) (Rule, error) {
+	var rule unionRule
+	for _, item := range items {
+		offset := utf8.RuneCountInString(repeat[:item.Offset])
+		if strings.TrimSpace(item.Value) == "" {
+			return nil, &RuleError{Code: CodeEmpty, Message: "empty repeat rule", Offset: offset, part: -1}
+		}
+
+		subRule, err := parseRule(item.Value)
+		var ruleErr *RuleError
+		if errors.As(err, &ruleErr) {
+			ruleErr.Offset += offset
+			return nil, ruleErr
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		conflict := func(message string) error {
+			token := strings.TrimSpace(item.Value)
+			return &RuleError{
+				Code:    CodeConflict,
+				Message: message,
+				Token:   token,
+				Offset:  offset + utf8.RuneCountInString(item.Value[:strings.Index(item.Value, token)]),
+				part:    -1,
+			}
+		}
+
+		// Occurrences and completions are counted for the whole task, not for a single rule
+		if OccurrenceCount(subRule) > 0 {
+			return nil, conflict("count can't be used in combined rules")
+		}
+		if isHabit(subRule) {
+			return nil, conflict("habit rule can't be used in combined rules")
+		}
+		rule.Rules = append(rule.Rules, subRule)
+	}
+	return rule, nil
+}
+
+// Next returns the earliest next date of the rules. Ended rules are skipped.
+func (r unionRule) Next(now, from time.Time) (time.Time, error) {
+	var next time.Time
+	for _, rule := range r.Rules {
+		date, err := rule.Next(now, from)
+		if errors.Is(err, ErrNoMoreOccurrences) {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		if next.IsZero() || date.Before(next) {
+			next = date
+		}
+	}
+	if next.IsZero() {
+		return time.Time{}, ErrNoMoreOccurrences
+	}
+	return next, nil
+}
+
+func (r unionRule) String() string {
+	rules := make([]string, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules = append(rules, rule.String())
+	}
+	return strings.Join(rules, unionSeparator+" ")
+}
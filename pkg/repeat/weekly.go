@@ -0,0 +1,105 @@
+package repeat
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weeklyRule repeats on the given weekdays: w <weekdays> [/<interval>]
+type weeklyRule struct {
+	// Weekdays indices 1-7 (1=Monday, 7=Sunday), 0 is unused
+	Weekdays [8]bool
+	// Interval in weeks counted from the week of the start date, 0 if not specified
+	Interval int
+}
+
+// weeklyHandler handles 'w' rules
+type weeklyHandler struct{}
+
+// Parse parses weekly repeat rule: w <weekdays> [/<interval>]
+func (weeklyHandler) Parse(parts []string) (Rule, error) {
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, ruleError(CodeFormat, "invalid weekly rule format")
+	}
+
+	var rule weeklyRule
+	if len(parts) == 3 {
+		interval, err := parseInterval(parts, 2)
+		if err != nil {
+			return nil, err
+		}
+		rule.Interval = interval
+	}
+
+	for _, item := range splitList(parts[1]) {
+		day, err := strconv.Atoi(item.Value)
+		if err != nil {
+			return nil, itemError(CodeValue, 1, item, "invalid weekday")
+		}
+		if day < 1 || day > 7 {
+			return nil, itemError(CodeValue, 1, item, "weekday must be between 1 and 7")
+		}
+		rule.Weekdays[day] = true
+	}
+
+	return rule, nil
+}
+
+// Describe describes the weekly rule
+func (weeklyHandler) Describe(rule Rule, lang string) string {
+	return rule.(weeklyRule).describe(lang)
+}
+
+// Next jumps to the first day after 'now' and checks the week from there for a matching weekday
+func (r weeklyRule) Next(now, from time.Time) (time.Time, error) {
+	start := firstCandidate(now, from)
+	if r.Interval > 1 {
+		return r.nextInterval(start, from), nil
+	}
+
+	weekday := isoWeekday(start)
+	for i := 0; i < 7; i++ {
+		if r.Weekdays[(weekday+i-1)%7+1] {
+			return start.AddDate(0, 0, i), nil
+		}
+	}
+	return time.Time{}, errors.New("cannot find next date for weekdays")
+}
+
+// nextInterval finds the first matching weekday from start in weeks
+// which are a multiple of the interval apart from the week of the start date
+func (r weeklyRule) nextInterval(start, from time.Time) time.Time {
+	week := weekStart(start)
+	if offset := daysBetween(weekStart(from), week) / 7 % r.Interval; offset != 0 {
+		week = week.AddDate(0, 0, 7*(r.Interval-offset))
+		start = week
+	}
+
+	// The rest of the current week may have no rule days, the next active week always has
+	for {
+		for weekday := isoWeekday(start); weekday <= 7; weekday++ {
+			if r.Weekdays[weekday] {
+				return week.AddDate(0, 0, weekday-1)
+			}
+		}
+		week = week.AddDate(0, 0, 7*r.Interval)
+		start = week
+	}
+}
+
+func (r weeklyRule) String() string {
+	return "w " + joinFlags(r.Weekdays[:]) + intervalString(r.Interval)
+}
+
+// joinFlags joins indices of set flags with commas
+func joinFlags(flags []bool) string {
+	var items []string
+	for i, set := range flags {
+		if set {
+			items = append(items, strconv.Itoa(i))
+		}
+	}
+	return strings.Join(items, ",")
+}
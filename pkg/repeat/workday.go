@@ -0,0 +1,177 @@
+package repeat
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Workday modifiers for occurrences falling on non-working days
+const (
+	// shiftNext moves the occurrence to the next working day
+	shiftNext = "next"
+	// shiftPrev moves the occurrence to the previous working day
+	shiftPrev = "prev"
+	// shiftSkip drops the occurrence
+	shiftSkip = "skip"
+	// shiftNearest moves the occurrence to the nearest working day of the same month
+	shiftNearest = "nearest"
+)
+
+// maxWorkdaySearch protects against infinite loop while searching working days
+const maxWorkdaySearch = 1000
+
+// maxBusinessDays limits calendar days counted by business day rules, 400 working days take about 560
+const maxBusinessDays = 3 * 366
+
+// WorkCalendar tells working days from weekends and public holidays
+type WorkCalendar interface {
+	IsWorkday(date time.Time) bool
+}
+
+// weekendCalendar treats only Saturday and Sunday as non-working days
+type weekendCalendar struct{}
+
+func (weekendCalendar) IsWorkday(date time.Time) bool {
+	return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday
+}
+
+// workCalendar is used by business day rules
+var workCalendar WorkCalendar = weekendCalendar{}
+
+// SetWorkCalendar sets the calendar used by business day rules.
+// Nil calendar resets it to weekends only.
+func SetWorkCalendar(calendar WorkCalendar) {
+	if calendar == nil {
+		calendar = weekendCalendar{}
+	}
+	workCalendar = calendar
+}
+
+// businessRule repeats every Interval working days: b <number>
+type businessRule struct {
+	Interval int
+}
+
+// businessHandler handles 'b' rules
+type businessHandler struct{}
+
+// Parse parses business day repeat rule: b <number>
+func (businessHandler) Parse(parts []string) (Rule, error) {
+	if len(parts) != 2 {
+		return nil, ruleError(CodeFormat, "invalid business day rule format")
+	}
+
+	interval, err := strconv.Atoi(parts[1])
+	if err != nil || interval <= 0 || interval > 400 {
+		return nil, partError(CodeValue, 1, "invalid business day interval")
+	}
+	return businessRule{Interval: interval}, nil
+}
+
+// Describe describes the business rule
+func (businessHandler) Describe(rule Rule, lang string) string {
+	return rule.(businessRule).describe(lang)
+}
+
+// Next counts working days from the start date, or from the last working day not after 'now'
+// if the start date has passed, so the search doesn't depend on how old the task is
+func (r businessRule) Next(now, from time.Time) (time.Time, error) {
+	currentDate := from
+	if afterDay(now, from) {
+		currentDate = from.AddDate(0, 0, daysBetween(from, now))
+		for i := 0; afterDay(currentDate, from) && !workCalendar.IsWorkday(currentDate); i++ {
+			if i == maxWorkdaySearch {
+				return time.Time{}, errors.New("cannot find working day")
+			}
+			currentDate = currentDate.AddDate(0, 0, -1)
+		}
+	}
+
+	for counted, i := 0, 0; counted < r.Interval; i++ {
+		if i == maxBusinessDays {
+			return time.Time{}, errors.New("cannot find next working day")
+		}
+		currentDate = currentDate.AddDate(0, 0, 1)
+		if workCalendar.IsWorkday(currentDate) {
+			counted++
+		}
+	}
+	return currentDate, nil
+}
+
+func (r businessRule) String() string {
+	return "b " + strconv.Itoa(r.Interval)
+}
+
+// workdayRule moves or skips occurrences of a rule falling on non-working days:
+// <rule> workday next|prev|skip|nearest
+type workdayRule struct {
+	Rule
+	Shift string
+}
+
+// Next returns the next occurrence of the wrapped rule shifted to a working day
+func (r workdayRule) Next(now, from time.Time) (time.Time, error) {
+	current := now
+	for i := 0; i < maxWorkdaySearch; i++ {
+		occurrence, err := r.Rule.Next(current, from)
+		if err != nil {
+			return time.Time{}, err
+		}
+		current = occurrence
+
+		shifted, ok := r.shift(occurrence)
+		if ok && afterDay(shifted, now) {
+			return shifted, nil
+		}
+	}
+	return time.Time{}, errors.New("cannot find next working date")
+}
+
+// shift moves the date to a working day according to the modifier
+func (r workdayRule) shift(date time.Time) (time.Time, bool) {
+	step := 1
+	switch r.Shift {
+	case shiftSkip:
+		return date, workCalendar.IsWorkday(date)
+	case shiftNearest:
+		return nearestWorkday(date)
+	case shiftPrev:
+		step = -1
+	}
+
+	for i := 0; i < maxWorkdaySearch; i++ {
+		if workCalendar.IsWorkday(date) {
+			return date, true
+		}
+		date = date.AddDate(0, 0, step)
+	}
+	return date, false
+}
+
+// nearestWorkday finds the nearest working day in the month of the date, the earlier one on a tie.
+// As with W in cron, Saturday moves to Friday and Sunday to Monday unless it crosses the month.
+func nearestWorkday(date time.Time) (time.Time, bool) {
+	if workCalendar.IsWorkday(date) {
+		return date, true
+	}
+	lastDay := daysIn(date.Year(), date.Month())
+	for distance := 1; distance < lastDay; distance++ {
+		if date.Day()-distance >= 1 {
+			if prev := date.AddDate(0, 0, -distance); workCalendar.IsWorkday(prev) {
+				return prev, true
+			}
+		}
+		if date.Day()+distance <= lastDay {
+			if next := date.AddDate(0, 0, distance); workCalendar.IsWorkday(next) {
+				return next, true
+			}
+		}
+	}
+	return date, false
+}
+
+func (r workdayRule) String() string {
+	return r.Rule.String() + " workday " + r.Shift
+}
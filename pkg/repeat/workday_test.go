@@ -0,0 +1,58 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// holidayCalendar treats weekends and the given dates as non-working days
+type holidayCalendar map[string]bool
+
+func (c holidayCalendar) IsWorkday(date time.Time) bool {
+	return weekendCalendar{}.IsWorkday(date) && !c[date.Format(DateFormat)]
+}
+
+func TestBusinessNext(t *testing.T) {
+	tbl := []struct {
+		now    string
+		date   string
+		repeat string
+		want   string
+	}{
+		{"20240126", "20240122", "b 2", "20240130"},
+		{"20240127", "20240101", "b 1", "20240129"},
+		{"20240126", "20240201", "b 1", "20240202"},
+		{"20240126", "20240125", "b 5", "20240202"},
+		// Old tasks are counted from the last working day before now
+		{"20240126", "00010101", "b 1", "20240129"},
+		{"20240128", "19000101", "b 3", "20240131"},
+	}
+	for _, v := range tbl {
+		now, err := time.Parse(DateFormat, v.now)
+		assert.NoError(t, err)
+		next, err := NextDate(now, v.date, v.repeat)
+		if assert.NoError(t, err, "%q %q from %s", v.date, v.repeat, v.now) {
+			assert.Equal(t, v.want, next, "%q %q from %s", v.date, v.repeat, v.now)
+		}
+	}
+}
+
+func TestBusinessIterator(t *testing.T) {
+	SetWorkCalendar(holidayCalendar{"20240130": true})
+	defer SetWorkCalendar(nil)
+
+	rule, err := Parse("b 1")
+	assert.NoError(t, err)
+	now, _ := time.Parse(DateFormat, "20240126")
+	from, _ := time.Parse(DateFormat, "00010101")
+
+	dates, err := NewIterator(rule, now, from).Take(4, time.Time{})
+	assert.NoError(t, err)
+	var got []string
+	for _, date := range dates {
+		got = append(got, date.Format(DateFormat))
+	}
+	assert.Equal(t, []string{"20240129", "20240131", "20240201", "20240202"}, got)
+}
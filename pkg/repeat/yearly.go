@@ -0,0 +1,142 @@
+package repeat
+
+import (
+	"errors"
+	"time"
+)
+
+// February 29 policies for non-leap years
+const (
+	// leapClamp moves the occurrence to February 28
+	leapClamp = "clamp"
+	// leapRoll moves the occurrence to March 1
+	leapRoll = "roll"
+	// leapSkip drops the occurrence
+	leapSkip = "skip"
+)
+
+// maxLeapYears protects against infinite loop, leap years may be 8 years apart
+const maxLeapYears = 9
+
+// yearlyRule repeats every year on the start date: y [clamp|roll|skip]
+// Without a policy February 29 becomes March 1 and stays there.
+// With a policy the series started on February 29 returns to it in leap years,
+// see WithStart for tasks whose date is already moved.
+type yearlyRule struct {
+	Policy string
+	// LeapStart is set when the series started on February 29
+	LeapStart bool
+}
+
+// yearlyHandler handles 'y' rules
+type yearlyHandler struct{}
+
+// Parse parses yearly repeat rule: y [clamp|roll|skip]
+func (yearlyHandler) Parse(parts []string) (Rule, error) {
+	switch {
+	case len(parts) == 1:
+		return yearlyRule{}, nil
+	case len(parts) == 2 && (parts[1] == leapClamp || parts[1] == leapRoll || parts[1] == leapSkip):
+		return yearlyRule{Policy: parts[1]}, nil
+	case len(parts) == 2:
+		return nil, partError(CodeValue, 1, "february 29 policy must be clamp, roll or skip")
+	default:
+		return nil, ruleError(CodeFormat, "invalid yearly rule format")
+	}
+}
+
+// Describe describes the yearly rule
+func (yearlyHandler) Describe(rule Rule, lang string) string {
+	return rule.(yearlyRule).describe(lang)
+}
+
+// Next jumps straight to the year of 'now', at least one year from the start date
+func (r yearlyRule) Next(now, from time.Time) (time.Time, error) {
+	if r.Policy != "" && (r.LeapStart || from.Month() == time.February && from.Day() == 29) {
+		return r.nextLeapDay(now, from)
+	}
+
+	years := max(1, now.Year()-from.Year())
+	next := addYears(from, years)
+	if !afterDay(next, now) {
+		next = addYears(from, years+1)
+	}
+	return next, nil
+}
+
+// nextLeapDay finds the next February 29, or its replacement in non-leap years
+func (r yearlyRule) nextLeapDay(now, from time.Time) (time.Time, error) {
+	year := max(from.Year()+1, now.Year())
+	for i := 0; i < maxLeapYears; i, year = i+1, year+1 {
+		month, day := time.February, 29
+		if !isLeap(year) {
+			switch r.Policy {
+			case leapSkip:
+				continue
+			case leapClamp:
+				day = 28
+			case leapRoll:
+				month, day = time.March, 1
+			}
+		}
+
+		next := time.Date(year, month, day,
+			from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), from.Location())
+		if afterDay(next, now) {
+			return next, nil
+		}
+	}
+	return time.Time{}, errors.New("cannot find next leap year")
+}
+
+// addYears adds years to the date. February 29 becomes March 1 after the first
+// year and stays there, as if years were added one at a time.
+func addYears(date time.Time, years int) time.Time {
+	if date.Month() != time.February || date.Day() != 29 {
+		return date.AddDate(years, 0, 0)
+	}
+	return time.Date(date.Year()+years, time.March, 1,
+		date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+}
+
+// isLeap checks if the year has February 29
+func isLeap(year int) bool {
+	return daysIn(year, time.February) == 29
+}
+
+func (r yearlyRule) String() string {
+	if r.Policy == "" {
+		return "y"
+	}
+	return "y " + r.Policy
+}
+
+// WithStart returns the rule of a series which started on the date, when the task date
+// may be a moved occurrence: February 29 under clamp or roll policy in non-leap years
+func WithStart(rule Rule, start time.Time) Rule {
+	switch r := rule.(type) {
+	case yearlyRule:
+		r.LeapStart = start.Month() == time.February && start.Day() == 29
+		return r
+	case handledRule:
+		r.Rule = WithStart(r.Rule, start)
+		return r
+	case limitedRule:
+		r.Rule = WithStart(r.Rule, start)
+		return r
+	case workdayRule:
+		r.Rule = WithStart(r.Rule, start)
+		return r
+	case exceptionRule:
+		r.Rule = WithStart(r.Rule, start)
+		return r
+	case unionRule:
+		rules := make([]Rule, 0, len(r.Rules))
+		for _, item := range r.Rules {
+			rules = append(rules, WithStart(item, start))
+		}
+		return unionRule{Rules: rules}
+	default:
+		return rule
+	}
+}
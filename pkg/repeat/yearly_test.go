@@ -0,0 +1,38 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYearlyStart(t *testing.T) {
+	tbl := []struct {
+		now    string
+		date   string
+		start  string
+		repeat string
+		want   string
+	}{
+		{"20270301", "20270228", "20240229", "y clamp", "20280229"},
+		{"20270302", "20270301", "20240229", "y roll", "20280229"},
+		{"20270301", "20270228", "20250228", "y clamp", "20280228"},
+		{"20270302", "20270301", "20250301", "y roll", "20280301"},
+		{"20270301", "20270228", "20240229", "y", "20280228"},
+		{"20270301", "20270228", "20240229", "y clamp; m 15", "20270315"},
+		{"20280101", "20270228", "20240229", "y clamp count 5", "20280229"},
+	}
+	for _, v := range tbl {
+		now, _ := time.Parse(DateFormat, v.now)
+		date, _ := time.Parse(DateFormat, v.date)
+		start, _ := time.Parse(DateFormat, v.start)
+
+		rule, err := Parse(v.repeat)
+		assert.NoError(t, err)
+		next, err := WithStart(rule, start).Next(now, date)
+		if assert.NoError(t, err, "%q from %s", v.repeat, v.start) {
+			assert.Equal(t, v.want, next.Format(DateFormat), "%q from %s", v.repeat, v.start)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+)
+
+const (
+	// defaultTrashRetention - how long deleted tasks are kept in the trash by default
+	defaultTrashRetention = 30 * 24 * time.Hour
+	// purgeInterval - how often the trash is checked for expired tasks
+	purgeInterval = time.Hour
+)
+
+// startTrashPurge permanently deletes tasks kept in the trash longer than retention,
+// at start and then every purgeInterval
+func startTrashPurge(database *db.DB, retention time.Duration) {
+	purge := func() {
+		purged, err := database.PurgeTrash(time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("Trash purge error: %v", err)
+			return
+		}
+		if purged > 0 {
+			log.Printf("Purged %d tasks from trash", purged)
+		}
+	}
+
+	purge()
+	go func() {
+		for range time.Tick(purgeInterval) {
+			purge()
+		}
+	}()
+}
@@ -6,15 +6,22 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/AngryM0e/ya-p-golang-final/pkg/api"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/calendar"
 	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/AngryM0e/ya-p-golang-final/pkg/repeat"
 )
 
 type Config struct {
 	Port int
 	WebDir string
 	DBPath string
+	HolidaysFile string
+	TimeZone string
+	// TrashRetention - how long deleted tasks are kept, e.g. "720h", 30 days if empty
+	TrashRetention string
 }
 
 // NewServer create & config HTTP-router
@@ -25,8 +32,8 @@ func NewServer(cfg Config) (*http.ServeMux, *db.DB, error) {
 	// Check exist dir web & file index.html
 	webPath := filepath.Join(cfg.WebDir, "index.html")
 	if _, err := os.Stat(webPath); os.IsNotExist(err) {
-		log.Printf("Directory doesn't exist", cfg.WebDir)
-		log.Println("Create directory 's' and put static files there", cfg.WebDir)
+		log.Printf("Directory doesn't exist: %s", cfg.WebDir)
+		log.Printf("Create directory %s and put static files there", cfg.WebDir)
 	} else {
 		// Check index.html
 		webPath := filepath.Join(cfg.WebDir, "index.html")
@@ -36,14 +43,61 @@ func NewServer(cfg Config) (*http.ServeMux, *db.DB, error) {
 		}
 	}
 
+	// WebAssembly module of repeat rules is built separately, see cmd/wasm
+	wasmPath := filepath.Join(cfg.WebDir, "wasm", "repeat.wasm")
+	if _, err := os.Stat(wasmPath); os.IsNotExist(err) {
+		log.Printf("WebAssembly module doesn't exist: %s", wasmPath)
+		log.Printf("Build it with: GOOS=js GOARCH=wasm go build -o %s ./cmd/wasm", wasmPath)
+	}
+
 	// Configure static files handler
 	router.Handle("/", http.FileServer(http.Dir(cfg.WebDir)))
 
+	// Load working days calendar for business day rules
+	if cfg.HolidaysFile != "" {
+		holidays, err := calendar.Load(cfg.HolidaysFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ошибка загрузки календаря: %w", err)
+		}
+		repeat.SetWorkCalendar(holidays)
+		api.SetWorkCalendar(holidays)
+		log.Printf("Using holidays calendar: %s", cfg.HolidaysFile)
+	}
+
+	// Set time zone of "today" for tasks
+	if cfg.TimeZone != "" {
+		loc, err := time.LoadLocation(cfg.TimeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ошибка загрузки часового пояса: %w", err)
+		}
+		api.SetTimeZone(loc)
+		log.Printf("Using time zone: %s", cfg.TimeZone)
+	}
+
+	// Check trash retention before opening DB
+	retention := defaultTrashRetention
+	if cfg.TrashRetention != "" {
+		var err error
+		retention, err = time.ParseDuration(cfg.TrashRetention)
+		if err != nil || retention <= 0 {
+			return nil, nil, fmt.Errorf("некорректный срок хранения корзины: %s", cfg.TrashRetention)
+		}
+	}
+
 	database, err := db.Init(cfg.DBPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("ошибка инициализации БД: %w", err)
 	}
 
+	// Make user-defined repeat presets available to rules
+	if err := api.LoadPresets(database); err != nil {
+		database.Close()
+		return nil, nil, fmt.Errorf("ошибка загрузки пресетов: %w", err)
+	}
+
+	// Empty the trash after the retention
+	startTrashPurge(database, retention)
+
 	// Initialize API
 	api.Init(router, database)
 
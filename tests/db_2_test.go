@@ -11,11 +11,16 @@ import (
 )
 
 type Task struct {
-	ID      int64  `db:"id"`
-	Date    string `db:"date"`
-	Title   string `db:"title"`
-	Comment string `db:"comment"`
-	Repeat  string `db:"repeat"`
+	ID         int64  `db:"id"`
+	Date       string `db:"date"`
+	Title      string `db:"title"`
+	Comment    string `db:"comment"`
+	Repeat     string `db:"repeat"`
+	Remaining  int64  `db:"remaining"`
+	TimeZone   string `db:"timezone"`
+	RepeatMode string `db:"repeat_mode"`
+	DeletedAt  string `db:"deleted_at"`
+	StartDate  string `db:"start_date"`
 }
 
 func count(db *sqlx.DB) (int, error) {
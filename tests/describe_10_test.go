@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type describe struct {
+	repeat string
+	lang   string
+	want   string
+}
+
+func TestDescribe(t *testing.T) {
+	tbl := []describe{
+		{"d 1", "ru", "каждый день"},
+		{"d 3", "en", "every 3 days"},
+		{"y", "", "каждый год"},
+		{"w 1,3", "ru", "каждую неделю по понедельникам и средам"},
+		{"m 1,-1 2,8", "en", "on the 1st and last day of February and August"},
+		{"n 2 2", "en", "on the second Tuesday of every month"},
+		{"m 5 workday next until 20270101", "ru", "в 5-й день каждого месяца, с переносом на следующий рабочий день, до 01.01.2027"},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10", "en", "every 2 weeks on Monday and Friday, 10 times"},
+		{"k 34", "ru", ""},
+		{"y", "de", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/repeat/describe?repeat=%s&lang=%s",
+			url.QueryEscape(v.repeat), v.lang)
+		body, err := getBody(urlPath)
+		assert.NoError(t, err)
+
+		var m map[string]string
+		assert.NoError(t, json.Unmarshal(body, &m))
+		if v.want == "" {
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		assert.Equal(t, v.want, m["description"], "%v", v)
+	}
+}
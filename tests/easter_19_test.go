@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEasterNextDate(t *testing.T) {
+	tbl := []policyNextDate{
+		{"20240101", "20240101", "e 0", "20240505"},
+		{"20240101", "20240101", "e 0 orthodox", "20240505"},
+		{"20240101", "20240101", "e 0 western", "20240331"},
+		{"20240506", "20240101", "e 0", "20250420"},
+		{"20260101", "20260101", "e 0", "20260412"},
+		{"20260101", "20260101", "e 0 western", "20260405"},
+		{"20270101", "20270101", "e 0 western", "20270328"},
+		{"20240101", "20240101", "e -48", "20240318"},
+		{"20240101", "20240101", "e 49", "20240623"},
+		{"20250101", "20250101", "e -47 western", "20250304"},
+		{"20240624", "20240101", "e 49", "20250608"},
+		{"20240101", "20240101", "e", ""},
+		{"20240101", "20240101", "e x", ""},
+		{"20240101", "20240101", "e 400", ""},
+		{"20240101", "20240101", "e 1 catholic", ""},
+		{"20240101", "20240101", "e 1 western 2", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=%s&date=%s&repeat=%s",
+			v.now, v.date, url.QueryEscape(v.repeat))
+		get, err := getBody(urlPath)
+		assert.NoError(t, err)
+		if v.want == "" {
+			var m map[string]string
+			assert.NoError(t, json.Unmarshal(get, &m))
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		assert.Equal(t, v.want, string(get), "%v", v)
+	}
+}
+
+func TestEasterDescribe(t *testing.T) {
+	tbl := []describe{
+		{"e 0", "ru", "каждый год в православную Пасху"},
+		{"e 49", "ru", "каждый год через 49 дней после православной Пасхи"},
+		{"e -2 western", "en", "every year 2 days before Western Easter"},
+		{"e 1 western", "en", "every year 1 day after Western Easter"},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/repeat/describe?repeat=%s&lang=%s",
+			url.QueryEscape(v.repeat), v.lang)
+		body, err := getBody(urlPath)
+		assert.NoError(t, err)
+
+		var m map[string]string
+		assert.NoError(t, json.Unmarshal(body, &m))
+		assert.Equal(t, v.want, m["description"], "%v", v)
+	}
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getExceptions(t *testing.T, id string) []map[string]string {
+	body, err := requestJSON("api/task/exceptions?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+
+	var m map[string][]map[string]string
+	err = json.Unmarshal(body, &m)
+	assert.NoError(t, err)
+	return m["exceptions"]
+}
+
+func TestExceptions(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	day := func(days int) string {
+		return now.AddDate(0, 0, days).Format("20060102")
+	}
+
+	id := addTask(t, task{
+		date:   day(0),
+		title:  "Полить цветы",
+		repeat: "d 1",
+	})
+
+	// Skip tomorrow and move the day after tomorrow
+	ret, err := postJSON("api/task/exceptions", map[string]any{
+		"id":   id,
+		"date": day(1),
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	ret, err = postJSON("api/task/exceptions", map[string]any{
+		"id":       id,
+		"date":     day(2),
+		"new_date": day(5),
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	for _, v := range []map[string]any{
+		{"id": id, "date": "ooops"},
+		{"id": id, "date": day(3), "new_date": day(3)},
+		{"id": "7645346343", "date": day(3)},
+	} {
+		ret, err = postJSON("api/task/exceptions", v, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ret["error"], "Ожидается ошибка для %v", v)
+	}
+
+	exceptions := getExceptions(t, id)
+	assert.Equal(t, []map[string]string{
+		{"date": day(1)},
+		{"date": day(2), "new_date": day(5)},
+	}, exceptions)
+
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	var task Task
+	err = db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, day(3), task.Date)
+
+	ret, err = postJSON("api/task/exceptions?id="+id+"&date="+day(1), nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	assert.Len(t, getExceptions(t, id), 1)
+
+	// Exceptions are kept in the trash for the task to be restored, but not shown
+	ret, err = postJSON("api/task?id="+id, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	var count int
+	err = db.Get(&count, `SELECT count(*) FROM exceptions WHERE task_id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	ret, err = postJSON("api/task/exceptions?id="+id+"&date="+day(2), nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+	for _, query := range []string{"?id=" + id, "?id=7645346343"} {
+		body, err := requestJSON("api/task/exceptions"+query, nil, http.MethodGet)
+		assert.NoError(t, err)
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal(body, &m))
+		assert.NotEmpty(t, m["error"], query)
+	}
+}
+
+func TestMovedOccurrenceDone(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	day := func(days int) string {
+		return now.AddDate(0, 0, days).Format("20060102")
+	}
+
+	id := addTask(t, task{
+		date:   day(1),
+		title:  "Вынести мусор",
+		repeat: "d 7",
+	})
+	ret, err := postJSON("api/task/exceptions", map[string]any{
+		"id":       id,
+		"date":     day(8),
+		"new_date": day(10),
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	// The series goes on from the original date of the moved occurrence
+	for _, want := range []string{day(10), day(15), day(22)} {
+		ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+
+		var task Task
+		err = db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id)
+		assert.NoError(t, err)
+		assert.Equal(t, want, task.Date)
+	}
+}
+
+func TestExceptionNotStoredOnError(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("20060102")
+	id := addTask(t, task{
+		date:   tomorrow,
+		title:  "Последний раз",
+		repeat: "d 1 until " + tomorrow,
+	})
+
+	// Skipping the last occurrence leaves no date to move the task to
+	ret, err := postJSON("api/task/exceptions", map[string]any{
+		"id":   id,
+		"date": tomorrow,
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+	assert.Empty(t, getExceptions(t, id))
+}
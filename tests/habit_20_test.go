@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHabitNextDate(t *testing.T) {
+	tbl := []policyNextDate{
+		{"20240126", "20240101", "h 3 w", "20240127"},
+		{"20240126", "20240201", "h 2 m", "20240201"},
+		{"20240126", "20240101", "h 8 w", ""},
+		{"20240126", "20240101", "h 32 m", ""},
+		{"20240126", "20240101", "h 2 d", ""},
+		{"20240126", "20240101", "h 2", ""},
+		{"20240126", "20240101", "h 2 w count 3", ""},
+		{"20240126", "20240101", "h 2 w; d 1", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=%s&date=%s&repeat=%s",
+			v.now, v.date, url.QueryEscape(v.repeat))
+		get, err := getBody(urlPath)
+		assert.NoError(t, err)
+		if v.want == "" {
+			var m map[string]string
+			assert.NoError(t, json.Unmarshal(get, &m))
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		assert.Equal(t, v.want, string(get), "%v", v)
+	}
+
+	body, err := getBody("api/repeat/describe?lang=ru&repeat=" + url.QueryEscape("h 3 w"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"description":"3 раза в неделю"}`, string(body))
+}
+
+func TestHabitDone(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+
+	now := time.Now()
+	id := addTask(t, task{
+		date:   now.Format("20060102"),
+		title:  "Спортзал",
+		repeat: "h 2 w",
+	})
+
+	findTask := func() map[string]string {
+		for _, task := range getTasks(t, "") {
+			if task["id"] == id {
+				return task
+			}
+		}
+		return nil
+	}
+
+	habit := findTask()
+	assert.NotNil(t, habit)
+	assert.Equal(t, "0/2", habit["progress"])
+
+	ret, err := postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	var task Task
+	assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+	assert.Equal(t, now.AddDate(0, 0, 1).Format("20060102"), task.Date)
+
+	habit = findTask()
+	assert.NotNil(t, habit)
+	assert.Equal(t, "1/2", habit["progress"])
+
+	// The quota is met, the task is hidden until next Monday
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+	assert.Equal(t, now.AddDate(0, 0, 8-weekday).Format("20060102"), task.Date)
+	assert.Nil(t, findTask())
+
+	var count int
+	assert.NoError(t, db.Get(&count, `SELECT count FROM completions WHERE task_id=?`, id))
+	assert.Equal(t, 2, count)
+
+	// Completions are kept in the trash for the task to be restored
+	ret, err = postJSON("api/task?id="+id, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	assert.NoError(t, db.Get(&count, `SELECT count FROM completions WHERE task_id=?`, id))
+	assert.Equal(t, 2, count)
+}
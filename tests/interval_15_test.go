@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntervalRules(t *testing.T) {
+	tbl := []nextDate{
+		{"20240101", "w 1,3 /2", "20240129"},
+		{"20240108", "w 1,3 /2", "20240205"},
+		{"20240115", "m 15 /3", "20240415"},
+		{"20240201", "m 31 /1", "20240229"},
+		{"20231130", "m 31 /3", "20240229"},
+		{"20240101", "m -1 /2", "20240131"},
+		{"20240101", "w 1 /0", ""},
+		{"20240101", "w 1 /101", ""},
+		{"20240101", "m 15 /x", ""},
+		{"20240101", "w 1 2", ""},
+		{"20240101", "m 15 3 /2", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=20240126&date=%s&repeat=%s",
+			url.QueryEscape(v.date), url.QueryEscape(v.repeat))
+		get, err := getBody(urlPath)
+		assert.NoError(t, err)
+		next := string(get)
+		if v.want == "" {
+			var m map[string]string
+			assert.NoError(t, json.Unmarshal(get, &m))
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		assert.Equal(t, v.want, next, `{%q, %q, %q}`, v.date, v.repeat, v.want)
+	}
+
+	get, err := getBody("api/nextdate?now=20240126&date=20240101&repeat=" + url.QueryEscape("w 1,3 /2") + "&count=3")
+	assert.NoError(t, err)
+	var dates []string
+	assert.NoError(t, json.Unmarshal(get, &dates))
+	assert.Equal(t, []string{"20240129", "20240131", "20240212"}, dates)
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationsApplied(t *testing.T) {
+	database := openDB(t)
+	defer database.Close()
+
+	var versions []int
+	assert.NoError(t, database.Select(&versions, `SELECT version FROM schema_migrations ORDER BY version`))
+	assert.NotEmpty(t, versions)
+	for i, version := range versions {
+		assert.Equal(t, i+1, version)
+	}
+}
+
+func TestMigrateLegacyDB(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "legacy.db")
+
+	// Database created before migrations, with a column added by an older version
+	legacy, err := sqlx.Connect("sqlite", dbFile)
+	assert.NoError(t, err)
+	_, err = legacy.Exec(`
+		CREATE TABLE scheduler (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			date CHAR(8) NOT NULL DEFAULT "19700101",
+			title VARCHAR(256) NOT NULL DEFAULT "",
+			comment TEXT NOT NULL DEFAULT "",
+			repeat VARCHAR(128) NOT NULL DEFAULT "",
+			remaining INTEGER NOT NULL DEFAULT 0
+		);
+		INSERT INTO scheduler (date, title, repeat) VALUES ("20240126", "Старая задача", "d 1");`)
+	assert.NoError(t, err)
+	assert.NoError(t, legacy.Close())
+
+	database, err := db.Open(dbFile)
+	assert.NoError(t, err)
+	statuses, err := database.MigrationStatus()
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		assert.Empty(t, status.AppliedAt, "Миграция %d не должна быть применена", status.Version)
+	}
+
+	applied, err := database.Migrate()
+	assert.NoError(t, err)
+	assert.Equal(t, len(statuses), applied)
+
+	applied, err = database.Migrate()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, applied)
+	assert.NoError(t, database.Close())
+
+	database, err = db.Init(dbFile)
+	assert.NoError(t, err)
+	task, err := database.GetTaskByID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Старая задача", task.Title)
+	assert.Equal(t, "fixed", task.RepeatMode)
+	assert.NoError(t, database.Close())
+}
@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextDateOldTasks(t *testing.T) {
+	// Old tasks and sparse rules, which don't fit in a day-by-day scan
+	tbl := []nextDate{
+		{"16000101", "d 1", "20240127"},
+		{"10000101", "d 400", "20250127"},
+		{"18000101", "w 7", "20240128"},
+		{"17000101", "m -1 2", "20240229"},
+		{"20240301", "m 29 2", "20280229"},
+		{"20240229", "y skip", "20280229"},
+		{"20240301", "m 31 2,4,6,9,11", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=20240126&date=%s&repeat=%s",
+			url.QueryEscape(v.date), url.QueryEscape(v.repeat))
+		get, err := getBody(urlPath)
+		assert.NoError(t, err)
+		next := strings.TrimSpace(string(get))
+		_, err = time.Parse("20060102", next)
+		if err != nil && len(v.want) == 0 {
+			continue
+		}
+		assert.Equal(t, v.want, next, `{%q, %q, %q}`,
+			v.date, v.repeat, v.want)
+	}
+}
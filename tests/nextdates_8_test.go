@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nextDates struct {
+	date   string
+	repeat string
+	query  string
+	want   []string
+}
+
+func TestNextDates(t *testing.T) {
+	tbl := []nextDates{
+		{"20240125", "w 1,3", "count=5", []string{"20240129", "20240131", "20240205", "20240207", "20240212"}},
+		{"20240120", "d 20", "count=3", []string{"20240209", "20240229", "20240320"}},
+		{"20240127", "m -1", "until=20240501", []string{"20240131", "20240229", "20240331", "20240430"}},
+		{"20240229", "y", "count=3&until=20260101", []string{"20250301"}},
+		{"20240101", "n 2 2", "count=3", []string{"20240213", "20240312", "20240409"}},
+		{"20240101", "n -1 5", "count=2", []string{"20240223", "20240329"}},
+		{"20240101", "n 1,-1 1 1,7", "count=3", []string{"20240129", "20240701", "20240729"}},
+		{"20240101", "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "count=3", []string{"20240129", "20240202", "20240212"}},
+		{"20240101", "RRULE:FREQ=MONTHLY;BYMONTHDAY=1,-1;BYMONTH=2,8", "count=3", []string{"20240201", "20240229", "20240801"}},
+		{"20240101", "RRULE:FREQ=DAILY;COUNT=30", "count=10", []string{"20240127", "20240128", "20240129", "20240130"}},
+		{"20240131", "RRULE:FREQ=MONTHLY;UNTIL=20240601T000000Z", "count=10", []string{"20240131", "20240331", "20240531"}},
+		{"20240101", "c 0 9 * * 1-5", "count=4", []string{"20240129", "20240130", "20240131", "20240201"}},
+		{"20240101", "c 1,15 */3 *", "count=3", []string{"20240401", "20240415", "20240701"}},
+		{"20240101", "c 13 * FRI", "count=3", []string{"20240202", "20240209", "20240213"}},
+		{"20240120", "d 7 until 20240215", "count=10", []string{"20240127", "20240203", "20240210"}},
+		{"20240129", "w 1 count 3", "count=10", []string{"20240129", "20240205", "20240212"}},
+		{"20240129", "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3", "count=10", []string{"20240129", "20240205", "20240212"}},
+		{"20240101", "w 1 count 3", "count=10", []string{}},
+		{"20240101", "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3", "count=10", []string{}},
+		{"20240101", "w 1 count 5", "count=10", []string{"20240129"}},
+		{"20240122", "b 2", "count=3", []string{"20240130", "20240201", "20240205"}},
+		{"20240101", "m 10 workday next", "count=3", []string{"20240212", "20240311", "20240410"}},
+		{"20240501", "m 1 workday prev", "count=2", []string{"20240501", "20240531"}},
+		{"20240125", "w 1,3", "count=0", nil},
+		{"20240125", "w 1,3", "count=abc", nil},
+		{"20240125", "k 34", "count=3", nil},
+		{"20240101", "n 6 1", "count=3", nil},
+		{"20240101", "RRULE:FREQ=HOURLY", "count=3", nil},
+		{"20240101", "c 60 9 * * *", "count=3", nil},
+		{"20240101", "c * *", "count=3", nil},
+		{"20240101", "d 7 count 0", "count=3", nil},
+		{"20240101", "w 1 workday later", "count=3", nil},
+		{"20240101", "d 7 until 2024", "count=3", nil},
+		{"20240101", "RRULE:FREQ=DAILY;COUNT=3;UNTIL=20240601", "count=3", nil},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=20240126&date=%s&repeat=%s&%s",
+			url.QueryEscape(v.date), url.QueryEscape(v.repeat), v.query)
+		body, err := getBody(urlPath)
+		assert.NoError(t, err)
+
+		if v.want == nil {
+			var m map[string]any
+			assert.NoError(t, json.Unmarshal(body, &m))
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		var dates []string
+		assert.NoError(t, json.Unmarshal(body, &dates))
+		assert.Equal(t, v.want, dates, "%v", v)
+	}
+}
+
+func TestCountTask(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	// A future task date is the first of the occurrences, whichever syntax the count is in
+	start := time.Now().AddDate(0, 0, 7)
+	weekday := strconv.Itoa((int(start.Weekday())+6)%7 + 1)
+	for _, repeat := range []string{"w " + weekday + " count 3", "RRULE:FREQ=WEEKLY;COUNT=3"} {
+		id := addTask(t, task{
+			date:   start.Format("20060102"),
+			title:  "Три раза",
+			repeat: repeat,
+		})
+		var task Task
+		assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+		assert.Equal(t, start.Format("20060102"), task.Date, repeat)
+		assert.Equal(t, int64(2), task.Remaining, repeat)
+
+		body, err := getBody("api/nextdate?date=" + task.Date + "&repeat=" + url.QueryEscape(repeat) + "&count=10")
+		assert.NoError(t, err)
+		var dates []string
+		assert.NoError(t, json.Unmarshal(body, &dates))
+		assert.Len(t, dates, 3, repeat)
+	}
+
+	// Occurrences of a past task date are over
+	for _, repeat := range []string{"w 1 count 3", "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3"} {
+		ret, err := postJSON("api/task", map[string]any{"date": "20240101", "title": "Прошло", "repeat": repeat}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.Equal(t, "no_occurrences", ret["code"], repeat)
+	}
+}
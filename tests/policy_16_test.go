@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type policyNextDate struct {
+	now    string
+	date   string
+	repeat string
+	want   string
+}
+
+func TestEndOfMonthPolicy(t *testing.T) {
+	tbl := []policyNextDate{
+		{"20240301", "20240229", "y", "20250301"},
+		{"20240301", "20240229", "y clamp", "20250228"},
+		{"20250301", "20250228", "y clamp", "20260228"},
+		{"20270301", "20270228", "y clamp", "20280228"},
+		{"20240301", "20240229", "y roll", "20250301"},
+		{"20270302", "20270301", "y roll", "20280301"},
+		{"20240301", "20240229", "y skip", "20280229"},
+		{"20240401", "20240315", "y clamp", "20250315"},
+		{"20240126", "20240101", "y sometimes", ""},
+		{"20240126", "20240101", "m -5", "20240127"},
+		{"20240201", "20240201", "m -31", "20240301"},
+		{"20240126", "20240101", "m -32", ""},
+		{"20240601", "20240601", "m 15 workday nearest", "20240614"},
+		{"20240901", "20240901", "m 15 workday nearest", "20240916"},
+		{"20240520", "20240515", "m 1 workday nearest", "20240603"},
+		{"20240801", "20240801", "m -1 workday nearest", "20240830"},
+		{"20240126", "20240101", "m 1 workday closest", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=%s&date=%s&repeat=%s",
+			v.now, v.date, url.QueryEscape(v.repeat))
+		get, err := getBody(urlPath)
+		assert.NoError(t, err)
+		if v.want == "" {
+			var m map[string]string
+			assert.NoError(t, json.Unmarshal(get, &m))
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		assert.Equal(t, v.want, string(get), "%v", v)
+	}
+}
+
+func TestLeapDayTask(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	// nextFebruary returns the first February 28, or 29 in leap years for leap day series, after the date
+	nextFebruary := func(after time.Time, leapDay bool) time.Time {
+		for year := after.Year(); ; year++ {
+			date := time.Date(year, time.February, 28, 0, 0, 0, 0, time.UTC)
+			if leapDay && time.Date(year, time.February, 29, 0, 0, 0, 0, time.UTC).Month() == time.February {
+				date = date.AddDate(0, 0, 1)
+			}
+			if date.After(after) {
+				return date
+			}
+		}
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, v := range []struct {
+		date    string
+		leapDay bool
+	}{
+		{"20240229", true},
+		// February 28 of a non-leap year isn't taken for a moved February 29
+		{"20250228", false},
+	} {
+		id := addTask(t, task{
+			date:   v.date,
+			title:  "День рождения",
+			repeat: "y clamp",
+		})
+
+		var task Task
+		assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+		first := nextFebruary(today, v.leapDay)
+		assert.Equal(t, first.Format("20060102"), task.Date, v.date)
+		assert.Equal(t, v.date, task.StartDate)
+
+		ret, err := postJSON("api/task/done?id="+id, nil, http.MethodPost)
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+
+		assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+		assert.Equal(t, nextFebruary(first, v.leapDay).Format("20060102"), task.Date, v.date)
+	}
+}
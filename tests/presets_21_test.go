@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepeatAliases(t *testing.T) {
+	tbl := []nextDate{
+		{"20240126", "daily", "20240127"},
+		{"20240126", "workdays", "20240129"},
+		{"20240126", "weekends", "20240127"},
+		{"20240115", "quarterly", "20240415"},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=20240126&date=%s&repeat=%s", v.date, v.repeat)
+		get, err := getBody(urlPath)
+		assert.NoError(t, err)
+		assert.Equal(t, v.want, string(get), "%v", v)
+	}
+}
+
+func TestRepeatPresets(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	for _, v := range []map[string]any{
+		{"name": "d", "repeat": "w 2"},
+		{"name": "daily", "repeat": "w 2"},
+		{"name": "Gym", "repeat": "w 2"},
+		{"name": "gym", "repeat": "w 9"},
+		{"name": "gym", "repeat": ""},
+	} {
+		ret, err := postJSON("api/repeat/presets", v, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ret["error"], "Ожидается ошибка для %v", v)
+	}
+
+	ret, err := postJSON("api/repeat/presets", map[string]any{"name": "gym", "repeat": "w 2,4"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	ret, err = postJSON("api/repeat/presets", map[string]any{"name": "gym2", "repeat": "gym"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Equal(t, "conflict", ret["code"])
+
+	body, err := requestJSON("api/repeat/presets", nil, http.MethodGet)
+	assert.NoError(t, err)
+	var presets struct {
+		Presets []struct {
+			Name    string `json:"name"`
+			Repeat  string `json:"repeat"`
+			Builtin bool   `json:"builtin"`
+		} `json:"presets"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &presets))
+	found := map[string]bool{}
+	for _, preset := range presets.Presets {
+		found[preset.Name] = preset.Builtin
+	}
+	assert.Contains(t, found, "gym")
+	assert.False(t, found["gym"])
+	assert.True(t, found["workdays"])
+
+	body, err = getBody("api/nextdate?now=20240126&date=20240126&repeat=gym")
+	assert.NoError(t, err)
+	assert.Equal(t, "20240130", string(body))
+
+	id := addTask(t, task{
+		date:   "20240126",
+		title:  "Тренировка",
+		repeat: "gym",
+	})
+
+	var before Task
+	assert.NoError(t, db.Get(&before, `SELECT * FROM scheduler WHERE id=?`, id))
+
+	// Preset edit applies to the task referring to it and moves it to the first date of the new rule
+	ret, err = postJSON("api/repeat/presets", map[string]any{"name": "gym", "repeat": "w 1"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	var after Task
+	assert.NoError(t, db.Get(&after, `SELECT * FROM scheduler WHERE id=?`, id))
+	beforeDate, err := time.Parse("20060102", before.Date)
+	assert.NoError(t, err)
+	afterDate, err := time.Parse("20060102", after.Date)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Monday, afterDate.Weekday())
+	assert.False(t, afterDate.Before(beforeDate))
+	assert.True(t, afterDate.Before(beforeDate.AddDate(0, 0, 7)))
+	assert.Equal(t, before.StartDate, after.StartDate)
+
+	body, err = getBody("api/nextdate?now=20240126&date=20240126&repeat=gym")
+	assert.NoError(t, err)
+	assert.Equal(t, "20240129", string(body))
+
+	ret, err = postJSON("api/task?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+	assert.Equal(t, "gym", ret["repeat"])
+
+	body, err = getBody("api/repeat/describe?repeat=gym&lang=ru")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"description":"каждую неделю по понедельникам"}`, string(body))
+
+	ret, err = postJSON("api/repeat/presets?name=gym", nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// A task in the trash still refers to the preset
+	ret, err = postJSON("api/task?id="+id, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	ret, err = postJSON("api/repeat/presets?name=gym", nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	_, err = db.Exec(`DELETE FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+
+	ret, err = postJSON("api/repeat/presets?name=gym", nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	body, err = getBody("api/nextdate?now=20240126&date=20240126&repeat=gym")
+	assert.NoError(t, err)
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.NotEmpty(t, m["error"])
+}
+
+func TestPresetInUnion(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	ret, err := postJSON("api/repeat/presets", map[string]any{"name": "payday", "repeat": "m 5"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	// A preset may be one of combined rules
+	body, err := getBody("api/nextdate?now=20240126&date=20240126&repeat=" + url.QueryEscape("payday; m 20"))
+	assert.NoError(t, err)
+	assert.Equal(t, "20240205", string(body))
+
+	ret, err = postJSON("api/repeat/presets", map[string]any{"name": "payday2", "repeat": "m 1; payday"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Equal(t, "conflict", ret["code"])
+	assert.Equal(t, "payday", ret["token"])
+
+	id := addTask(t, task{
+		date:   "20240126",
+		title:  "Зарплата",
+		repeat: "payday; m 20",
+	})
+
+	ret, err = postJSON("api/repeat/presets?name=payday", nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// Count can't be used in combined rules, so the preset keeps its rule
+	ret, err = postJSON("api/repeat/presets", map[string]any{"name": "payday", "repeat": "m 5 count 3"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	body, err = getBody("api/nextdate?now=20240126&date=20240126&repeat=payday")
+	assert.NoError(t, err)
+	assert.Equal(t, "20240205", string(body))
+
+	_, err = db.Exec(`DELETE FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+
+	ret, err = postJSON("api/repeat/presets?name=payday", nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrevDate(t *testing.T) {
+	tbl := []policyNextDate{
+		{"20240126", "20240101", "d 7", "20240122"},
+		{"20240122", "20240101", "d 7", "20240122"},
+		{"20240110", "20240101", "w 1", "20240108"},
+		{"20240110", "20240101", "w 1,3", "20240110"},
+		{"20250310", "20200315", "y", "20240315"},
+		{"20240410", "20240101", "m 1,-1", "20240401"},
+		{"20240110", "20240101", "m 15", "20240101"},
+		{"20240110", "20240101", "d 7", "20240108"},
+		{"20240101", "20240101", "d 7", "20240101"},
+		{"20231231", "20240101", "d 7", ""},
+		{"20240110", "20240101", "k 1", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=%s&date=%s&repeat=%s&direction=prev",
+			v.now, v.date, url.QueryEscape(v.repeat))
+		get, err := getBody(urlPath)
+		assert.NoError(t, err)
+		if v.want == "" {
+			var m map[string]string
+			assert.NoError(t, json.Unmarshal(get, &m))
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		assert.Equal(t, v.want, string(get), "%v", v)
+	}
+
+	get, err := getBody("api/nextdate?now=20240126&date=20240101&repeat=d+7&direction=sideways")
+	assert.NoError(t, err)
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(get, &m))
+	assert.NotEmpty(t, m["error"])
+}
+
+func TestDatesBetween(t *testing.T) {
+	for _, v := range []struct {
+		date   string
+		repeat string
+		from   string
+		to     string
+		want   []string
+	}{
+		{"20240101", "d 7", "20240110", "20240131", []string{"20240115", "20240122", "20240129"}},
+		{"20240101", "m 1,15", "20240301", "20240331", []string{"20240301", "20240315"}},
+		{"20240101", "w 6,7", "20240105", "20240107", []string{"20240106", "20240107"}},
+		{"20240601", "m 1", "20240101", "20240731", []string{"20240601", "20240701"}},
+		{"20240110", "m 10 count 3", "20240201", "20240430", []string{"20240210", "20240310"}},
+		{"20240103", "w 1", "20240101", "20240110", []string{"20240103", "20240108"}},
+		{"20240101", "y", "20240201", "20241231", []string{}},
+	} {
+		urlPath := fmt.Sprintf("api/nextdate?date=%s&repeat=%s&from=%s&to=%s",
+			v.date, url.QueryEscape(v.repeat), v.from, v.to)
+		body, err := getBody(urlPath)
+		assert.NoError(t, err)
+
+		var dates []string
+		assert.NoError(t, json.Unmarshal(body, &dates), string(body))
+		assert.Equal(t, v.want, dates, "%v", v)
+	}
+
+	for _, urlPath := range []string{
+		"api/nextdate?date=20240101&repeat=d+1&from=20240201&to=20240101",
+		"api/nextdate?date=20240101&repeat=d+1&from=20240101",
+		"api/nextdate?date=20240101&repeat=d+1&from=20240101&to=20340101",
+	} {
+		body, err := getBody(urlPath)
+		assert.NoError(t, err)
+		var m map[string]string
+		assert.NoError(t, json.Unmarshal(body, &m))
+		assert.NotEmpty(t, m["error"], urlPath)
+	}
+}
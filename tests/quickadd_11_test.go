@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type quickAdd struct {
+	text   string
+	title  string
+	date   string
+	repeat string
+}
+
+func TestQuickAdd(t *testing.T) {
+	now := time.Now()
+	day := func(days int) string {
+		return now.AddDate(0, 0, days).Format("20060102")
+	}
+
+	monthly := now
+	for monthly.Day() != 5 {
+		monthly = monthly.AddDate(0, 0, 1)
+	}
+
+	tbl := []quickAdd{
+		{"Pay rent on the 5th every month", "Pay rent", monthly.Format("20060102"), "m 5"},
+		{"Позвонить маме завтра", "Позвонить маме", day(1), ""},
+		{"Полить цветы каждые 3 дня", "Полить цветы", day(0), "d 3"},
+		{"Take out trash every day starting tomorrow", "Take out trash starting", day(1), "d 1"},
+		{"Отчёт послезавтра", "Отчёт", day(2), ""},
+		{"Birthday party every year on 20250101", "Birthday party", "", "y"},
+		{"Visit dentist in 10 days", "Visit dentist", day(10), ""},
+	}
+	for _, v := range tbl {
+		ret, err := postJSON("api/task/quick", map[string]any{"text": v.text}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.Empty(t, ret["error"], "%v", v)
+		assert.Nil(t, ret["id"])
+		assert.Equal(t, v.title, ret["title"], "%v", v)
+		if v.date != "" {
+			assert.Equal(t, v.date, ret["date"], "%v", v)
+		}
+		if v.repeat == "" {
+			assert.Nil(t, ret["repeat"], "%v", v)
+		} else {
+			assert.Equal(t, v.repeat, ret["repeat"], "%v", v)
+		}
+	}
+
+	for _, text := range []string{"", "завтра", "every day", "Run every 500 days"} {
+		ret, err := postJSON("api/task/quick", map[string]any{"text": text}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ret["error"], "Ожидается ошибка для %q", text)
+	}
+
+	// Invalid rule is reported with its position
+	ret, err := postJSON("api/task/quick", map[string]any{"text": "Run every 500 days"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", ret["code"])
+	assert.Equal(t, "500", ret["token"])
+}
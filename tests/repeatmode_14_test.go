@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepeatMode(t *testing.T) {
+	body, err := getBody("api/nextdate?now=20240126&date=20240101&repeat=d+3&mode=relative")
+	assert.NoError(t, err)
+	assert.Equal(t, "20240129", string(body))
+
+	body, err = getBody("api/nextdate?now=20240126&date=20240101&repeat=d+3&mode=fixed")
+	assert.NoError(t, err)
+	assert.Equal(t, "20240128", string(body))
+
+	body, err = getBody("api/nextdate?now=20240126&date=20240101&repeat=d+3&mode=sometimes")
+	assert.NoError(t, err)
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.NotEmpty(t, m["error"])
+
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	day := func(days int) string {
+		return now.AddDate(0, 0, days).Format("20060102")
+	}
+
+	for _, v := range []struct {
+		mode string
+		want string
+	}{
+		{"", day(5)},
+		{"relative", day(3)},
+	} {
+		ret, err := postJSON("api/task", map[string]any{
+			"date":        day(-10),
+			"title":       "Полить цветы",
+			"repeat":      "d 3",
+			"repeat_mode": v.mode,
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		id := ret["id"]
+		assert.NotNil(t, id)
+
+		var task Task
+		assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+		assert.Equal(t, day(2), task.Date)
+
+		ret, err = postJSON("api/task/done?id="+fmt.Sprint(id), nil, http.MethodPost)
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+
+		assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+		assert.Equal(t, v.want, task.Date, "Режим %q", v.mode)
+	}
+
+	// Editing without the mode keeps it
+	ret, err := postJSON("api/task", map[string]any{
+		"title":       "Полить цветы",
+		"repeat":      "d 3",
+		"repeat_mode": "relative",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	id := fmt.Sprint(ret["id"])
+	ret, err = postJSON("api/task", map[string]any{
+		"id":     id,
+		"date":   day(1),
+		"title":  "Полить цветы вечером",
+		"repeat": "d 3",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	var task Task
+	assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+	assert.Equal(t, "relative", task.RepeatMode)
+
+	ret, err = postJSON("api/task", map[string]any{
+		"title":       "Неизвестный режим",
+		"repeat":      "d 3",
+		"repeat_mode": "sometimes",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+}
@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleTypes(t *testing.T) {
+	body, err := getBody("api/repeat/types")
+	assert.NoError(t, err)
+
+	var m map[string][]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	for _, ruleType := range []string{"d", "y", "w", "m", "n", "c", "b", "e", "h"} {
+		assert.Contains(t, m["types"], ruleType)
+	}
+
+	// Rules of registered types keep their modifiers, descriptions and errors
+	body, err = getBody("api/nextdate?now=20240126&date=20240101&repeat=w+6+workday+next")
+	assert.NoError(t, err)
+	assert.Equal(t, "20240129", string(body))
+
+	body, err = getBody("api/repeat/describe?lang=en&repeat=d+3")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"description":"every 3 days"}`, string(body))
+
+	ret, err := postJSON("api/repeat/validate", map[string]any{"repeat": "m 1 x"}, "POST")
+	assert.NoError(t, err)
+	assert.Equal(t, false, ret["valid"])
+	assert.Equal(t, "x", ret["token"])
+	assert.Equal(t, float64(4), ret["offset"])
+
+	ret, err = postJSON("api/repeat/validate", map[string]any{"repeat": "q 1"}, "POST")
+	assert.NoError(t, err)
+	assert.Equal(t, "unknown_type", ret["code"])
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeZone(t *testing.T) {
+	for _, zone := range []string{"Pacific/Kiritimati", "Pacific/Pago_Pago", "Europe/Moscow"} {
+		loc, err := time.LoadLocation(zone)
+		assert.NoError(t, err)
+		tomorrow := time.Now().In(loc).AddDate(0, 0, 1).Format("20060102")
+
+		body, err := getBody("api/nextdate?date=20240101&repeat=d+1&tz=" + zone)
+		assert.NoError(t, err)
+		assert.Equal(t, tomorrow, string(body), "Часовой пояс %s", zone)
+	}
+
+	body, err := getBody("api/nextdate?date=20240101&repeat=d+1&tz=Mars/Olympus")
+	assert.NoError(t, err)
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.NotEmpty(t, m["error"])
+
+	db := openDB(t)
+	defer db.Close()
+
+	ret, err := postJSON("api/task", map[string]any{
+		"title":    "Созвон с Владивостоком",
+		"repeat":   "d 1",
+		"timezone": "Asia/Vladivostok",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotNil(t, ret["id"])
+
+	loc, _ := time.LoadLocation("Asia/Vladivostok")
+	var task Task
+	err = db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, ret["id"])
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Vladivostok", task.TimeZone)
+	assert.Equal(t, time.Now().In(loc).Format("20060102"), task.Date)
+
+	body, err = requestJSON("api/tasks?tz=Mars/Olympus", nil, http.MethodGet)
+	assert.NoError(t, err)
+	m = nil
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.NotEmpty(t, m["error"])
+
+	// Editing without the zone keeps it
+	id := fmt.Sprint(ret["id"])
+	ret, err = postJSON("api/task", map[string]any{
+		"id":     id,
+		"title":  "Созвон с Владивостоком",
+		"date":   task.Date,
+		"repeat": "d 1",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	err = db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Vladivostok", task.TimeZone)
+	assert.Equal(t, time.Now().In(loc).AddDate(0, 0, 1).Format("20060102"), task.Date)
+
+	// An empty zone resets it to the server zone
+	ret, err = postJSON("api/task", map[string]any{
+		"id":       id,
+		"title":    "Созвон с Владивостоком",
+		"date":     task.Date,
+		"repeat":   "d 1",
+		"timezone": "",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	err = db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Empty(t, task.TimeZone)
+
+	ret, err = postJSON("api/task", map[string]any{
+		"title":    "Неизвестный пояс",
+		"timezone": "Mars/Olympus",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+}
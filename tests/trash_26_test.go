@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AngryM0e/ya-p-golang-final/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTrash(t *testing.T) []map[string]string {
+	body, err := requestJSON("api/trash", nil, http.MethodGet)
+	assert.NoError(t, err)
+
+	var m map[string][]map[string]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	return m["tasks"]
+}
+
+func findTask(tasks []map[string]string, id string) map[string]string {
+	for _, task := range tasks {
+		if task["id"] == id {
+			return task
+		}
+	}
+	return nil
+}
+
+func TestTrash(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	id := addTask(t, task{
+		date:    now.Format("20060102"),
+		title:   "Случайно удалённая задача",
+		comment: "восстановить",
+	})
+
+	ret, err := postJSON("api/task?id="+id, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	notFoundTask(t, id)
+
+	// The row is kept with the deletion time
+	var task Task
+	assert.NoError(t, db.Get(&task, `SELECT * FROM scheduler WHERE id=?`, id))
+	assert.NotEmpty(t, task.DeletedAt)
+
+	trashed := findTask(getTrash(t), id)
+	assert.NotNil(t, trashed)
+	assert.Equal(t, "Случайно удалённая задача", trashed["title"])
+	assert.NotEmpty(t, trashed["deleted_at"])
+
+	ret, err = postJSON("api/task/restore?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	assert.Nil(t, findTask(getTrash(t), id))
+
+	ret, err = postJSON("api/task?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+	assert.Equal(t, "восстановить", ret["comment"])
+
+	ret, err = postJSON("api/task/restore?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// A done non-repeating task goes to the trash as well
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	notFoundTask(t, id)
+	assert.NotNil(t, findTask(getTrash(t), id))
+
+	ret, err = postJSON("api/task/restore?id=abc", nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+}
+
+func TestPurgeTrash(t *testing.T) {
+	database, err := db.Init(filepath.Join(t.TempDir(), "trash.db"))
+	assert.NoError(t, err)
+	defer database.Close()
+
+	kept, err := database.AddTask(db.Task{Date: "20240126", Title: "Остаётся"})
+	assert.NoError(t, err)
+	deleted, err := database.AddTask(db.Task{Date: "20240126", Title: "В корзине", Repeat: "d 1"})
+	assert.NoError(t, err)
+	assert.NoError(t, database.SetException(db.Exception{TaskID: int(deleted), Date: "20240127"}))
+	assert.NoError(t, database.DeleteTask(int(deleted)))
+
+	// Tasks deleted after the time stay in the trash
+	purged, err := database.PurgeTrash(time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+
+	purged, err = database.PurgeTrash(time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	trash, err := database.GetTrash(10)
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+	exceptions, err := database.GetExceptions(int(deleted))
+	assert.NoError(t, err)
+	assert.Empty(t, exceptions)
+	_, err = database.GetTaskByID(int(kept))
+	assert.NoError(t, err)
+}
+
+func TestTrashedTaskNotUpdated(t *testing.T) {
+	database, err := db.Init(filepath.Join(t.TempDir(), "trash.db"))
+	assert.NoError(t, err)
+	defer database.Close()
+
+	id, err := database.AddTask(db.Task{Date: "20240126", Title: "В корзине", Repeat: "d 1"})
+	assert.NoError(t, err)
+	assert.NoError(t, database.DeleteTask(int(id)))
+
+	// A task deleted between reading and writing is not rescheduled or deleted again
+	assert.ErrorIs(t, database.UpdateTaskDate(int(id), "20240127"), db.ErrNotFound)
+	assert.ErrorIs(t, database.RescheduleTask(int(id), "20240127", 0), db.ErrNotFound)
+	assert.ErrorIs(t, database.DeleteTask(int(id)), db.ErrNotFound)
+
+	trash, err := database.GetTrash(10)
+	assert.NoError(t, err)
+	if assert.Len(t, trash, 1) {
+		assert.Equal(t, "20240126", trash[0].Date)
+	}
+}
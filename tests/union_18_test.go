@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnionRules(t *testing.T) {
+	tbl := []nextDate{
+		{"20240126", "m 1; w 5", "20240201"},
+		{"20240126", "m 15; w 5", "20240202"},
+		{"20240126", "RRULE:FREQ=WEEKLY;BYDAY=MO; m 1", "20240129"},
+		{"20240120", "m 1;RRULE:FREQ=DAILY;INTERVAL=10", "20240130"},
+		{"20240126", "d 3 until 20240127; y", "20250126"},
+		{"20240126", "m 1;", ""},
+		{"20240126", "m 1; w 9", ""},
+		{"20240126", "m 1; d 2 count 3", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=20240126&date=%s&repeat=%s",
+			url.QueryEscape(v.date), url.QueryEscape(v.repeat))
+		get, err := getBody(urlPath)
+		assert.NoError(t, err)
+		if v.want == "" {
+			var m map[string]string
+			assert.NoError(t, json.Unmarshal(get, &m))
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		assert.Equal(t, v.want, string(get), "%v", v)
+	}
+
+	get, err := getBody("api/nextdate?now=20240126&date=20240126&repeat=" + url.QueryEscape("m 1; w 5") + "&count=3")
+	assert.NoError(t, err)
+	var dates []string
+	assert.NoError(t, json.Unmarshal(get, &dates))
+	assert.Equal(t, []string{"20240201", "20240202", "20240209"}, dates)
+
+	ret, err := postJSON("api/repeat/validate", map[string]any{"repeat": "m 1; w 9"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Equal(t, "9", ret["token"])
+	assert.Equal(t, float64(7), ret["offset"])
+
+	get, err = getBody("api/repeat/describe?lang=en&repeat=" + url.QueryEscape("m 1; w 5"))
+	assert.NoError(t, err)
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(get, &m))
+	assert.Equal(t, "on the 1st day of every month; every week on Friday", m["description"])
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validate struct {
+	repeat string
+	code   string
+	token  string
+	offset float64
+}
+
+func TestValidate(t *testing.T) {
+	tbl := []validate{
+		{"w 1,3 /2", "", "", 0},
+		{"RRULE:FREQ=MONTHLY;BYMONTHDAY=1,-1", "", "", 0},
+		{"", "empty", "", 0},
+		{"w 1,8", "value", "8", 4},
+		{"m 15 /x", "value", "/x", 5},
+		{"k 34", "unknown_type", "k", 0},
+		{"d", "format", "d", 0},
+		{"d 5 until 2024", "value", "2024", 10},
+		{"d 5 count 3 count 4", "duplicate", "count", 4},
+		{"w 1 ж count x", "value", "x", 12},
+		{"c 0 25 * * *", "value", "25", 4},
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO,XX", "value", "XX", 27},
+		{"RRULE:FREQ=DAILY;COUNT=2;UNTIL=20250101", "conflict", "RRULE:FREQ=DAILY;COUNT=2;UNTIL=20250101", 0},
+		{"m 30 2", "no_occurrences", "m 30 2", 0},
+		{"c 31 2 *", "no_occurrences", "c 31 2 *", 0},
+		{"d 5 until 20200101", "", "", 0},
+	}
+	for _, v := range tbl {
+		ret, err := postJSON("api/repeat/validate", map[string]any{"repeat": v.repeat}, http.MethodPost)
+		assert.NoError(t, err)
+		if v.code == "" {
+			assert.Equal(t, true, ret["valid"], "%v", v)
+			assert.Nil(t, ret["error"], "%v", v)
+			continue
+		}
+		assert.Equal(t, false, ret["valid"], "%v", v)
+		assert.NotEmpty(t, ret["error"], "%v", v)
+		assert.Equal(t, v.code, ret["code"], "%v", v)
+		assert.Equal(t, v.token, ret["token"], "%v", v)
+		assert.Equal(t, v.offset, ret["offset"], "%v", v)
+	}
+
+	ret, err := postJSON("api/task", map[string]any{
+		"date":   "20240126",
+		"title":  "Неверное правило",
+		"repeat": "w 1,9",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Equal(t, "weekday must be between 1 and 7", ret["error"])
+	assert.Equal(t, "9", ret["token"])
+	assert.Equal(t, float64(4), ret["offset"])
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// goWasm runs the go command for the browser target in the module root
+func goWasm(args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = ".."
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	return cmd.CombinedOutput()
+}
+
+func TestWasmBuild(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command is not available")
+	}
+
+	out, err := goWasm("build", "-o", filepath.Join(t.TempDir(), "repeat.wasm"), "./cmd/wasm")
+	assert.NoError(t, err, string(out))
+
+	// Exported functions are tested in the module itself, which runs under Node.js
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("Node.js is not available to run the WebAssembly module")
+	}
+	goroot, err := exec.Command("go", "env", "GOROOT").Output()
+	assert.NoError(t, err)
+	wasmExec := filepath.Join(strings.TrimSpace(string(goroot)), "lib", "wasm", "go_js_wasm_exec")
+	out, err = goWasm("test", "-count=1", "-exec="+wasmExec, "./cmd/wasm")
+	assert.NoError(t, err, string(out))
+}
+
+func TestWasmAsset(t *testing.T) {
+	// The server serves the module from the web directory, where it's built like in cmd/wasm
+	if _, err := os.Stat("../web/wasm/repeat.wasm"); os.IsNotExist(err) {
+		if _, err := exec.LookPath("go"); err != nil {
+			t.Skip("WebAssembly module is not built and go command is not available")
+		}
+		out, err := goWasm("build", "-o", "web/wasm/repeat.wasm", "./cmd/wasm")
+		assert.NoError(t, err, string(out))
+	}
+
+	resp, err := http.Get(getURL("wasm/repeat.wasm"))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	// Browsers compile streamed modules only with this content type
+	assert.Equal(t, "application/wasm", resp.Header.Get("Content-Type"))
+}
+
+func TestWasmCalendar(t *testing.T) {
+	body, err := getBody("api/repeat/calendar")
+	assert.NoError(t, err)
+
+	var m struct {
+		TimeZone *string  `json:"time_zone"`
+		Holidays []string `json:"holidays"`
+		Workdays []string `json:"workdays"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.NotNil(t, m.TimeZone)
+	assert.NotNil(t, m.Holidays)
+	assert.NotNil(t, m.Workdays)
+}